@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/ProlificLabs/captrivia/models"
+)
+
+// The request and response bodies of the API the bots use, only with the fields they need.
+// They are kept here so the bot depends on the wire format, not on the server packages.
+
+type startGameRequest struct {
+	Name        string `json:"name"`
+	Multiplayer bool   `json:"multiplayer"`
+	Questions   int    `json:"questions"`
+	Capacity    int    `json:"capacity"`
+}
+
+type joinGameRequest struct {
+	GameID string `json:"gameId"`
+	Name   string `json:"name"`
+}
+
+// gameSession is the response of starting and joining a game
+type gameSession struct {
+	GameID    string `json:"gameId"`
+	SessionID string `json:"sessionId"`
+	PlayerID  string `json:"playerId"`
+}
+
+type gameView struct {
+	ID        string            `json:"id"`
+	Questions []models.Question `json:"questions"`
+}
+
+type answerRequest struct {
+	GameID     string          `json:"gameId"`
+	SessionID  string          `json:"sessionId"`
+	QuestionID string          `json:"questionId"`
+	Answer     json.RawMessage `json:"answer"`
+}
+
+type answerResult struct {
+	Correct      bool `json:"correct"`
+	CurrentScore int  `json:"currentScore"`
+}
+
+type endGameRequest struct {
+	GameID    string `json:"gameId"`
+	SessionID string `json:"sessionId"`
+}
+
+type gameEnd struct {
+	FinalScore int  `json:"finalScore"`
+	Finished   bool `json:"finished"`
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/models"
+)
+
+// Bot is a player that joins games and answers with its strategy, its client keeps its player ID across games
+type Bot struct {
+	Name     string
+	Strategy Strategy
+	client   *Client
+	random   *rand.Rand
+}
+
+// GameConfig is what every game of a run looks like
+type GameConfig struct {
+	Questions int
+	// Longest pause before each answer, bots pick a random pause up to it
+	Think time.Duration
+	// How long to wait for the game to start and finish, every countdown takes 11 seconds and the hub runs one at a time
+	Timeout time.Duration
+}
+
+// seat is a bot in a game
+type seat struct {
+	bot       *Bot
+	sessionID string
+	socket    *GameSocket
+}
+
+// PlayGame has the first bot start a multiplayer game and the others join it, every bot connects to
+// the websocket, the first one starts the game and every bot answers every question and ends the game
+func PlayGame(bots []*Bot, config GameConfig) error {
+	owner := bots[0]
+	var started gameSession
+	request := startGameRequest{Name: owner.Name, Multiplayer: true, Questions: config.Questions, Capacity: len(bots)}
+	if err := owner.client.post("start", "/game/start", request, &started); err != nil {
+		return err
+	}
+
+	seats := []*seat{{bot: owner, sessionID: started.SessionID}}
+	for _, bot := range bots[1:] {
+		var joined gameSession
+		if err := bot.client.post("join", "/game/join", joinGameRequest{GameID: started.GameID, Name: bot.Name}, &joined); err != nil {
+			return err
+		}
+		seats = append(seats, &seat{bot: bot, sessionID: joined.SessionID})
+	}
+
+	for _, seat := range seats {
+		socket, err := seat.bot.client.Connect(started.GameID, seat.sessionID)
+		if err != nil {
+			return err
+		}
+		defer socket.Close()
+		seat.socket = socket
+
+		// The hub sends the players once the socket is registered, the countdown would be missed before that
+		if err := waitFor(seat.bot.client, socket, "allPlayers", config.Timeout); err != nil {
+			return err
+		}
+	}
+
+	if err := seats[0].socket.Send(models.Message{Type: "startGame", ID: started.GameID}); err != nil {
+		return fmt.Errorf("send startGame: %w", err)
+	}
+
+	errs := make([]error, len(seats))
+	var wait sync.WaitGroup
+	for i := range seats {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			errs[i] = seats[i].play(started.GameID, config)
+		}(i)
+	}
+	wait.Wait()
+	return errors.Join(errs...)
+}
+
+// play waits for the game to start, answers every question, ends the game and waits for it to finish
+func (seat *seat) play(gameID string, config GameConfig) error {
+	client := seat.bot.client
+	if err := waitFor(client, seat.socket, "startGame", config.Timeout); err != nil {
+		return err
+	}
+
+	var game gameView
+	if err := client.get("get game", "/game/"+gameID+"/"+seat.sessionID, &game); err != nil {
+		return err
+	}
+
+	bot := seat.bot
+	for _, question := range game.Questions {
+		if config.Think > 0 {
+			time.Sleep(time.Duration(bot.random.Int63n(int64(config.Think))))
+		}
+
+		answer := answerRequest{GameID: gameID, SessionID: seat.sessionID, QuestionID: question.ID}
+		answer.Answer, _ = json.Marshal(bot.Strategy.Answer(question, bot.random))
+		var result answerResult
+		if err := client.post("answer", "/answer", answer, &result); err != nil {
+			return err
+		}
+		client.stats.RecordAnswer(bot.Strategy.Name(), result.Correct)
+	}
+
+	var ended gameEnd
+	if err := client.post("end", "/game/end", endGameRequest{GameID: gameID, SessionID: seat.sessionID}, &ended); err != nil {
+		return err
+	}
+	return waitFor(client, seat.socket, "gameFinished", config.Timeout)
+}
+
+// waitFor waits for a message and records how long it took
+func waitFor(client *Client, socket *GameSocket, messageType string, timeout time.Duration) error {
+	started := time.Now()
+	_, err := socket.WaitFor(messageType, timeout)
+	client.stats.Record("wait "+messageType, time.Since(started), err)
+	return err
+}
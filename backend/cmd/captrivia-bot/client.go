@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/models"
+	"github.com/ProlificLabs/captrivia/routes"
+	"github.com/gorilla/websocket"
+)
+
+var errTimeout = errors.New("timed out waiting for a message")
+
+// RequestError is a response of the API with an error status
+type RequestError struct {
+	Status  int
+	Code    string `json:"code"`
+	Message string `json:"error"`
+}
+
+func (err *RequestError) Error() string {
+	return fmt.Sprintf("%d %s: %s", err.Status, err.Code, err.Message)
+}
+
+// Client calls the versioned API of a server and records every call in the stats.
+// It keeps the identity cookie the server issues, so every player needs their own client.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	stats   *Stats
+}
+
+func NewClient(baseURL string, stats *Stats) *Client {
+	jar, _ := cookiejar.New(nil)
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/") + routes.APIPrefix, http: &http.Client{Timeout: 30 * time.Second, Jar: jar}, stats: stats}
+}
+
+func (client *Client) post(operation string, path string, request interface{}, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	return client.do(operation, http.MethodPost, path, bytes.NewReader(body), response)
+}
+
+func (client *Client) get(operation string, path string, response interface{}) error {
+	return client.do(operation, http.MethodGet, path, nil, response)
+}
+
+func (client *Client) do(operation string, method string, path string, body io.Reader, response interface{}) error {
+	started := time.Now()
+	err := client.request(method, path, body, response)
+	client.stats.Record(operation, time.Since(started), err)
+	if err != nil {
+		return fmt.Errorf("%s: %w", operation, err)
+	}
+	return nil
+}
+
+func (client *Client) request(method string, path string, body io.Reader, response interface{}) error {
+	req, err := http.NewRequest(method, client.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		requestErr := &RequestError{Status: resp.StatusCode}
+		json.NewDecoder(resp.Body).Decode(requestErr)
+		return requestErr
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
+
+// Connect opens the websocket of a game for the session, messages are read until the connection closes
+func (client *Client) Connect(gameID string, sessionID string) (*GameSocket, error) {
+	url := "ws" + strings.TrimPrefix(client.baseURL, "http") + "/game/" + gameID + "/ws?sessionId=" + sessionID
+
+	started := time.Now()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	client.stats.Record("connect", time.Since(started), err)
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+
+	socket := &GameSocket{conn: conn, messages: make(chan models.Message, 256)}
+	go socket.read()
+	return socket, nil
+}
+
+// GameSocket is the websocket of a player in a game
+type GameSocket struct {
+	conn     *websocket.Conn
+	messages chan models.Message
+}
+
+func (socket *GameSocket) read() {
+	defer close(socket.messages)
+	for {
+		var message models.Message
+		if err := socket.conn.ReadJSON(&message); err != nil {
+			return
+		}
+		socket.messages <- message
+	}
+}
+
+func (socket *GameSocket) Send(message models.Message) error {
+	return socket.conn.WriteJSON(message)
+}
+
+// WaitFor skips messages until one of the type arrives
+func (socket *GameSocket) WaitFor(messageType string, timeout time.Duration) (models.Message, error) {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case message, ok := <-socket.messages:
+			if !ok {
+				return models.Message{}, fmt.Errorf("the connection closed waiting for %s", messageType)
+			}
+			if message.Type == messageType {
+				return message, nil
+			}
+		case <-deadline:
+			return models.Message{}, fmt.Errorf("%w %s", errTimeout, messageType)
+		}
+	}
+}
+
+func (socket *GameSocket) Close() error {
+	return socket.conn.Close()
+}
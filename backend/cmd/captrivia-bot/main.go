@@ -0,0 +1,100 @@
+// Command captrivia-bot simulates players of multiplayer games to load-test a server. Bots start and join
+// games over HTTP, follow them over the websocket and answer with pluggable strategies. It reports the
+// latency percentiles and error rate of every operation, and the accuracy of every strategy.
+//
+//	go run ./cmd/captrivia-bot -url http://localhost:8080 -games 20 -concurrency 5 -players 4 -strategies random,cheat,skill
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/models"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:8080", "URL of the server")
+	games := flag.Int("games", 10, "number of games to play")
+	concurrency := flag.Int("concurrency", 5, "number of games played at the same time")
+	players := flag.Int("players", 4, "number of bots in every game")
+	questions := flag.Int("questions", 5, "number of questions in every game")
+	strategies := flag.String("strategies", "random", "comma separated strategies given to the bots in turn: random, first, cheat or skill")
+	skill := flag.Float64("skill", 0.7, "how often the skill strategy knows the answer, from 0 to 1")
+	bankPath := flag.String("bank", "questions.json", "question bank the cheat and skill strategies look answers up in")
+	think := flag.Duration("think", 0, "longest pause of a bot before each answer")
+	timeout := flag.Duration("timeout", 2*time.Minute, "how long to wait for a game to start or finish, the hub runs one countdown at a time")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed of the answers and pauses of the bots")
+	flag.Parse()
+
+	if *games < 1 || *concurrency < 1 || *players < 1 || *questions < 1 {
+		log.Fatal("games, concurrency, players and questions must be at least 1")
+	}
+
+	var bank map[string]models.Question
+	if strings.Contains(*strategies, "cheat") || strings.Contains(*strategies, "skill") {
+		var err error
+		bank, err = LoadBank(*bankPath)
+		if err != nil {
+			log.Fatalf("Failed to load the question bank: %v", err)
+		}
+	}
+
+	var playerStrategies []Strategy
+	for _, name := range strings.Split(*strategies, ",") {
+		strategy, err := NewStrategy(strings.TrimSpace(name), bank, *skill)
+		if err != nil {
+			log.Fatal(err)
+		}
+		playerStrategies = append(playerStrategies, strategy)
+	}
+
+	stats := NewStats()
+	config := GameConfig{Questions: *questions, Think: *think, Timeout: *timeout}
+
+	// Every game gets its own bots, they keep their player ID across the games of the run
+	random := rand.New(rand.NewSource(*seed))
+	gameBots := make([][]*Bot, *concurrency)
+	for worker := range gameBots {
+		for player := 0; player < *players; player++ {
+			number := worker**players + player + 1
+			gameBots[worker] = append(gameBots[worker], &Bot{
+				Name:     fmt.Sprintf("Bot %d", number),
+				client:   NewClient(*url, stats),
+				Strategy: playerStrategies[(number-1)%len(playerStrategies)],
+				random:   rand.New(rand.NewSource(random.Int63())),
+			})
+		}
+	}
+
+	log.Printf("Playing %d games of %d bots against %s, %d at a time", *games, *players, *url, *concurrency)
+	started := time.Now()
+	pending := make(chan int, *games)
+	for game := 0; game < *games; game++ {
+		pending <- game
+	}
+	close(pending)
+
+	var wait sync.WaitGroup
+	for _, bots := range gameBots {
+		wait.Add(1)
+		go func(bots []*Bot) {
+			defer wait.Done()
+			for game := range pending {
+				err := PlayGame(bots, config)
+				if err != nil {
+					log.Printf("Game %d failed: %v", game+1, err)
+				}
+				stats.RecordGame(err)
+			}
+		}(bots)
+	}
+	wait.Wait()
+
+	stats.Report(os.Stdout, time.Since(started))
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Stats records the latency and the errors of every operation the bots do, and how well each strategy answers
+type Stats struct {
+	lock        sync.Mutex
+	operations  map[string]*operationStats
+	strategies  map[string]*strategyStats
+	games       int
+	failedGames int
+}
+
+type operationStats struct {
+	latencies []time.Duration
+	errors    int
+	// Failed requests by error code, or by status when the response has no code
+	errorCodes map[string]int
+}
+
+type strategyStats struct {
+	answered int
+	correct  int
+}
+
+func NewStats() *Stats {
+	return &Stats{operations: make(map[string]*operationStats), strategies: make(map[string]*strategyStats)}
+}
+
+// Record adds an operation that took latency, err is nil when it succeeded
+func (stats *Stats) Record(operation string, latency time.Duration, err error) {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	recorded, exists := stats.operations[operation]
+	if !exists {
+		recorded = &operationStats{errorCodes: make(map[string]int)}
+		stats.operations[operation] = recorded
+	}
+	recorded.latencies = append(recorded.latencies, latency)
+	if err != nil {
+		recorded.errors++
+		recorded.errorCodes[errorCode(err)]++
+	}
+}
+
+func (stats *Stats) RecordAnswer(strategy string, correct bool) {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	recorded, exists := stats.strategies[strategy]
+	if !exists {
+		recorded = &strategyStats{}
+		stats.strategies[strategy] = recorded
+	}
+	recorded.answered++
+	if correct {
+		recorded.correct++
+	}
+}
+
+// RecordGame counts a game, err is nil when every player finished it
+func (stats *Stats) RecordGame(err error) {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	stats.games++
+	if err != nil {
+		stats.failedGames++
+	}
+}
+
+// Report writes the latency percentiles and error rate of every operation and the accuracy of every strategy
+func (stats *Stats) Report(w io.Writer, elapsed time.Duration) {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	fmt.Fprintf(w, "%d games in %v, %d failed\n\n", stats.games, elapsed.Round(time.Millisecond), stats.failedGames)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "operation\tcount\trate/s\terrors\terror rate\tp50\tp90\tp99\tmax\t")
+	for _, operation := range sortedKeys(stats.operations) {
+		recorded := stats.operations[operation]
+		latencies := append([]time.Duration{}, recorded.latencies...)
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		count := len(latencies)
+		fmt.Fprintf(table, "%s\t%d\t%.1f\t%d\t%.2f%%\t%v\t%v\t%v\t%v\t\n",
+			operation, count, float64(count)/elapsed.Seconds(), recorded.errors, 100*float64(recorded.errors)/float64(count),
+			percentile(latencies, 50), percentile(latencies, 90), percentile(latencies, 99), percentile(latencies, 100))
+	}
+	table.Flush()
+
+	for _, operation := range sortedKeys(stats.operations) {
+		for _, code := range sortedKeys(stats.operations[operation].errorCodes) {
+			fmt.Fprintf(w, "%s failed with %s %d times\n", operation, code, stats.operations[operation].errorCodes[code])
+		}
+	}
+
+	fmt.Fprintln(w)
+	table = tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "strategy\tanswered\tcorrect\taccuracy\t")
+	for _, strategy := range sortedKeys(stats.strategies) {
+		recorded := stats.strategies[strategy]
+		fmt.Fprintf(table, "%s\t%d\t%d\t%.1f%%\t\n", strategy, recorded.answered, recorded.correct, 100*float64(recorded.correct)/float64(recorded.answered))
+	}
+	table.Flush()
+}
+
+// percentile returns the latency that p percent of the sorted latencies are at or below
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted))*p/100+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index].Round(time.Microsecond)
+}
+
+func errorCode(err error) string {
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		if requestErr.Code != "" {
+			return requestErr.Code
+		}
+		return fmt.Sprintf("HTTP %d", requestErr.Status)
+	}
+	if errors.Is(err, errTimeout) {
+		return "TIMEOUT"
+	}
+	return "NETWORK"
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
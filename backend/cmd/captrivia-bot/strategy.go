@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+
+	"github.com/ProlificLabs/captrivia/models"
+)
+
+// Strategy picks the answer of a bot to a question, the answer has the shape of the question type
+type Strategy interface {
+	Name() string
+	Answer(question models.Question, random *rand.Rand) interface{}
+}
+
+// Strategies are created by name, the bank is only read by the strategies that need it
+func NewStrategy(name string, bank map[string]models.Question, skill float64) (Strategy, error) {
+	switch name {
+	case "random":
+		return randomStrategy{}, nil
+	case "first":
+		return firstStrategy{}, nil
+	case "cheat":
+		return cheatStrategy{bank: bank}, nil
+	case "skill":
+		if skill < 0 || skill > 1 {
+			return nil, fmt.Errorf("skill must be between 0 and 1, got %v", skill)
+		}
+		return skillStrategy{cheat: cheatStrategy{bank: bank}, accuracy: skill}, nil
+	default:
+		return nil, fmt.Errorf("unknown strategy %q, use random, first, cheat or skill", name)
+	}
+}
+
+// randomStrategy guesses
+type randomStrategy struct{}
+
+func (randomStrategy) Name() string {
+	return "random"
+}
+
+func (randomStrategy) Answer(question models.Question, random *rand.Rand) interface{} {
+	switch question.Kind() {
+	case models.QuestionTrueFalse:
+		return random.Intn(2) == 1
+	case models.QuestionMultiSelect:
+		picked := make([]int, 0, len(question.Options))
+		for i := range question.Options {
+			if random.Intn(2) == 1 {
+				picked = append(picked, i)
+			}
+		}
+		if len(picked) == 0 {
+			picked = append(picked, random.Intn(len(question.Options)))
+		}
+		return picked
+	case models.QuestionNumeric:
+		return float64(random.Intn(1000))
+	case models.QuestionOrdering:
+		return random.Perm(len(question.Options))
+	default:
+		return random.Intn(len(question.Options))
+	}
+}
+
+// firstStrategy always picks the first option, like the old Python bot
+type firstStrategy struct{}
+
+func (firstStrategy) Name() string {
+	return "first"
+}
+
+func (firstStrategy) Answer(question models.Question, random *rand.Rand) interface{} {
+	switch question.Kind() {
+	case models.QuestionTrueFalse:
+		return true
+	case models.QuestionMultiSelect:
+		return []int{0}
+	case models.QuestionNumeric:
+		return 0.0
+	case models.QuestionOrdering:
+		order := make([]int, len(question.Options))
+		for i := range order {
+			order[i] = i
+		}
+		return order
+	default:
+		return 0
+	}
+}
+
+// cheatStrategy looks the answer up in a copy of the question bank, it guesses generated questions
+type cheatStrategy struct {
+	bank map[string]models.Question
+}
+
+func (cheatStrategy) Name() string {
+	return "cheat"
+}
+
+func (strategy cheatStrategy) Answer(question models.Question, random *rand.Rand) interface{} {
+	if known, exists := strategy.bank[question.ID]; exists {
+		return known.Solution()
+	}
+	return randomStrategy{}.Answer(question, random)
+}
+
+// skillStrategy knows the answer with the probability of its accuracy and guesses otherwise
+type skillStrategy struct {
+	cheat    cheatStrategy
+	accuracy float64
+}
+
+func (strategy skillStrategy) Name() string {
+	return fmt.Sprintf("skill %.0f%%", 100*strategy.accuracy)
+}
+
+func (strategy skillStrategy) Answer(question models.Question, random *rand.Rand) interface{} {
+	if random.Float64() < strategy.accuracy {
+		return strategy.cheat.Answer(question, random)
+	}
+	return randomStrategy{}.Answer(question, random)
+}
+
+// LoadBank reads the question bank file of the server, keyed by question ID
+func LoadBank(path string) (map[string]models.Question, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var questions []models.Question
+	if err := json.Unmarshal(content, &questions); err != nil {
+		return nil, err
+	}
+
+	bank := make(map[string]models.Question, len(questions))
+	for _, question := range questions {
+		bank[question.ID] = question
+	}
+	return bank, nil
+}
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecatedRouteMiddleware marks responses of legacy routes as deprecated and links to the
+// same route under the prefix of the current version
+func DeprecatedRouteMiddleware(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		successor := prefix + c.Request.URL.Path
+		if c.Request.URL.RawQuery != "" {
+			successor += "?" + c.Request.URL.RawQuery
+		}
+
+		c.Header("Deprecation", "true")
+		c.Header("Link", "<"+successor+">; rel=\"successor-version\"")
+		c.Next()
+	}
+}
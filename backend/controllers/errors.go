@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/ProlificLabs/captrivia/i18n"
+	"github.com/ProlificLabs/captrivia/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCode is a stable, machine-readable identifier of an error response
+type ErrorCode string
+
+const (
+	CodeInvalidRequestBody   ErrorCode = "INVALID_REQUEST_BODY"
+	CodeInvalidParameter     ErrorCode = "INVALID_PARAMETER"
+	CodeValidationFailed     ErrorCode = "VALIDATION_FAILED"
+	CodeGameNotFound         ErrorCode = "GAME_NOT_FOUND"
+	CodeSessionNotFound      ErrorCode = "SESSION_NOT_FOUND"
+	CodeQuestionNotFound     ErrorCode = "QUESTION_NOT_FOUND"
+	CodePlayerNotFound       ErrorCode = "PLAYER_NOT_FOUND"
+	CodeTicketNotFound       ErrorCode = "TICKET_NOT_FOUND"
+	CodeTicketExpired        ErrorCode = "TICKET_EXPIRED"
+	CodeTicketReplaced       ErrorCode = "TICKET_REPLACED"
+	CodeTournamentNotFound   ErrorCode = "TOURNAMENT_NOT_FOUND"
+	CodeCategoryNotFound     ErrorCode = "CATEGORY_NOT_FOUND"
+	CodeGameAlreadyFinished  ErrorCode = "GAME_ALREADY_FINISHED"
+	CodeGameFull             ErrorCode = "GAME_FULL"
+	CodeSinglePlayerGame     ErrorCode = "SINGLE_PLAYER_GAME"
+	CodeInvalidName          ErrorCode = "INVALID_NAME"
+	CodeNameTaken            ErrorCode = "NAME_TAKEN"
+	CodePlayerBanned         ErrorCode = "PLAYER_BANNED"
+	CodeInvalidAnswer        ErrorCode = "INVALID_ANSWER"
+	CodeQuestionSkipped      ErrorCode = "QUESTION_SKIPPED"
+	CodeOptionRemoved        ErrorCode = "OPTION_REMOVED"
+	CodePowerUpUnavailable   ErrorCode = "POWER_UP_UNAVAILABLE"
+	CodeReviewNotAvailable   ErrorCode = "REVIEW_NOT_AVAILABLE"
+	CodeNotGameOwner         ErrorCode = "NOT_GAME_OWNER"
+	CodeOwnerCannotBeRemoved ErrorCode = "OWNER_CANNOT_BE_REMOVED"
+	CodeDailyAlreadyPlayed   ErrorCode = "DAILY_ALREADY_PLAYED"
+	CodeDailyNeedsIdentity   ErrorCode = "DAILY_NEEDS_IDENTITY"
+	CodeNotOrganizer         ErrorCode = "NOT_TOURNAMENT_ORGANIZER"
+	CodeTournamentStarted    ErrorCode = "TOURNAMENT_ALREADY_STARTED"
+	CodeAlreadyRegistered    ErrorCode = "ALREADY_REGISTERED"
+	CodeNotEnoughPlayers     ErrorCode = "NOT_ENOUGH_PLAYERS"
+	CodeMatchNotFound        ErrorCode = "TOURNAMENT_MATCH_NOT_FOUND"
+	CodeNotTournamentPlayer  ErrorCode = "NOT_TOURNAMENT_PLAYER"
+	CodeInternalError        ErrorCode = "INTERNAL_ERROR"
+)
+
+// APIError is the error of a failed request, ErrorMiddleware renders it as the response
+type APIError struct {
+	Status int
+	Code   ErrorCode
+	// Catalog message in the locale of the request, Message is used when it is not set
+	Key     i18n.Key
+	Message string
+	Details gin.H
+	// Reasons of the fields that failed validation, added to the details in the locale of the request
+	fields fieldErrors
+}
+
+func (err *APIError) Error() string {
+	if err.Message != "" {
+		return string(err.Code) + ": " + err.Message
+	}
+	return string(err.Code)
+}
+
+func newAPIError(status int, code ErrorCode, key i18n.Key) *APIError {
+	return &APIError{Status: status, Code: code, Key: key}
+}
+
+// WithDetails returns a copy of the error with extra information for the client
+func (err *APIError) WithDetails(details gin.H) *APIError {
+	withDetails := *err
+	withDetails.Details = details
+	return &withDetails
+}
+
+// WithFields returns a copy of the error with the reason of every field that failed validation
+func (err *APIError) WithFields(fields fieldErrors) *APIError {
+	withFields := *err
+	withFields.fields = fields
+	return &withFields
+}
+
+// localizedDetails returns the details with the reasons of the fields in the locale
+func (err *APIError) localizedDetails(locale string) gin.H {
+	if err.fields == nil {
+		return err.Details
+	}
+
+	details := gin.H{"fields": err.fields.localize(locale)}
+	for name, value := range err.Details {
+		details[name] = value
+	}
+	return details
+}
+
+// invalidParameter is the error of a query or path parameter that cannot be used
+func invalidParameter(name string, key i18n.Key) *APIError {
+	return newAPIError(http.StatusBadRequest, CodeInvalidParameter, key).WithDetails(gin.H{"parameter": name})
+}
+
+// Errors returned by the models and what the client is told about them
+var knownErrors = []struct {
+	err    error
+	status int
+	code   ErrorCode
+	// Catalog message, every locale of the catalog has it
+	key i18n.Key
+}{
+	{models.ErrGameNotFound, http.StatusNotFound, CodeGameNotFound, i18n.GameNotFound},
+	{models.ErrQuestionNotFound, http.StatusNotFound, CodeQuestionNotFound, i18n.QuestionNotFound},
+	{models.ErrTicketNotFound, http.StatusNotFound, CodeTicketNotFound, i18n.TicketNotFound},
+	{models.ErrTicketExpired, http.StatusGone, CodeTicketExpired, i18n.TicketExpired},
+	{models.ErrTicketReplaced, http.StatusConflict, CodeTicketReplaced, i18n.TicketReplaced},
+	{models.ErrTournamentNotFound, http.StatusNotFound, CodeTournamentNotFound, i18n.TournamentNotFound},
+	{ErrCategoryNotFound, http.StatusBadRequest, CodeCategoryNotFound, i18n.CategoryNotFound},
+	{models.ErrNameRequired, http.StatusBadRequest, CodeInvalidName, i18n.NameRequired},
+	{models.ErrNameTooLong, http.StatusBadRequest, CodeInvalidName, i18n.NameTooLong},
+	{models.ErrNameInvalidCharacters, http.StatusBadRequest, CodeInvalidName, i18n.NameInvalidCharacters},
+	{models.ErrNameNotAllowed, http.StatusBadRequest, CodeInvalidName, i18n.NameNotAllowed},
+	{models.ErrNameTaken, http.StatusBadRequest, CodeNameTaken, i18n.NameTaken},
+	{models.ErrPlayerBanned, http.StatusBadRequest, CodePlayerBanned, i18n.PlayerBanned},
+	{models.ErrInvalidAnswer, http.StatusBadRequest, CodeInvalidAnswer, i18n.InvalidAnswer},
+	{models.ErrPowerUpUsedUp, http.StatusConflict, CodePowerUpUnavailable, i18n.PowerUpUsedUp},
+	{models.ErrDoublePointsActive, http.StatusConflict, CodePowerUpUnavailable, i18n.DoublePointsActive},
+	{models.ErrNotEnoughWrongOptions, http.StatusBadRequest, CodePowerUpUnavailable, i18n.NotEnoughWrongOptions},
+	{models.ErrFiftyFiftyUnsupported, http.StatusBadRequest, CodePowerUpUnavailable, i18n.FiftyFiftyUnsupported},
+	{models.ErrDailyAttemptUsed, http.StatusConflict, CodeDailyAlreadyPlayed, i18n.DailyAttemptUsed},
+	{models.ErrTournamentStarted, http.StatusConflict, CodeTournamentStarted, i18n.TournamentStarted},
+	{models.ErrAlreadyRegistered, http.StatusConflict, CodeAlreadyRegistered, i18n.AlreadyRegistered},
+	{models.ErrNotEnoughPlayers, http.StatusBadRequest, CodeNotEnoughPlayers, i18n.NotEnoughPlayers},
+	{models.ErrTournamentMatchNotFound, http.StatusNotFound, CodeMatchNotFound, i18n.MatchNotFound},
+	{models.ErrNotTournamentPlayer, http.StatusForbidden, CodeNotTournamentPlayer, i18n.NotTournamentPlayer},
+}
+
+// toAPIError returns the API error for any error, errors the client cannot do anything about are internal errors
+func toAPIError(err error) *APIError {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError
+	}
+
+	for _, known := range knownErrors {
+		if errors.Is(err, known.err) {
+			return &APIError{Status: known.status, Code: known.code, Key: known.key, Message: err.Error()}
+		}
+	}
+	return newAPIError(http.StatusInternalServerError, CodeInternalError, i18n.InternalError)
+}
+
+// logError reports a failure that does not fail the request, like saving stats that are still kept in memory
+func logError(err error) {
+	log.Println("Error:", err)
+}
+
+// abortWithError stops the request, the error is rendered by ErrorMiddleware
+func abortWithError(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
+
+// ErrorMiddleware renders the last error of a request that did not write a response as
+// {"code": ..., "error": message, "details": {...}}
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		apiError := toAPIError(c.Errors.Last().Err)
+		body := gin.H{"code": apiError.Code, "error": apiError.Message}
+		if apiError.Key != "" {
+			body["error"] = message(c, apiError.Key)
+		}
+		if details := apiError.localizedDetails(requestLocale(c)); details != nil {
+			body["details"] = details
+		}
+		c.JSON(apiError.Status, body)
+	}
+}
+
+var (
+	errInvalidRequestBody   = newAPIError(http.StatusBadRequest, CodeInvalidRequestBody, i18n.InvalidRequestBody)
+	errSessionNotFound      = newAPIError(http.StatusNotFound, CodeSessionNotFound, i18n.SessionNotFound)
+	errPlayerNotFound       = newAPIError(http.StatusNotFound, CodePlayerNotFound, i18n.PlayerNotFound)
+	errGameAlreadyFinished  = newAPIError(http.StatusBadRequest, CodeGameAlreadyFinished, i18n.GameFinished)
+	errGameFull             = newAPIError(http.StatusBadRequest, CodeGameFull, i18n.GameFull)
+	errSinglePlayerGame     = newAPIError(http.StatusBadRequest, CodeSinglePlayerGame, i18n.SinglePlayerGame)
+	errQuestionSkipped      = newAPIError(http.StatusBadRequest, CodeQuestionSkipped, i18n.QuestionSkipped)
+	errOptionRemoved        = newAPIError(http.StatusBadRequest, CodeOptionRemoved, i18n.OptionRemoved)
+	errNoQuestionForPowerUp = newAPIError(http.StatusBadRequest, CodePowerUpUnavailable, i18n.NoQuestionForPowerUp)
+	errReviewNotAvailable   = newAPIError(http.StatusBadRequest, CodeReviewNotAvailable, i18n.ReviewNotAvailable)
+	errNotGameOwner         = newAPIError(http.StatusForbidden, CodeNotGameOwner, i18n.OwnerOnly)
+	errOwnerCannotBeRemoved = newAPIError(http.StatusBadRequest, CodeOwnerCannotBeRemoved, i18n.OwnerCannotBeRemoved)
+	errNotOrganizer         = newAPIError(http.StatusForbidden, CodeNotOrganizer, i18n.OrganizerOnly)
+	// The daily challenge is only played under the player ID the server issued, a new ID would be a new attempt
+	errDailyNeedsIdentity = newAPIError(http.StatusUnauthorized, CodeDailyNeedsIdentity, i18n.DailyNeedsIdentity)
+)
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// How often a comment is written to an idle event stream so proxies do not close it
+const eventStreamKeepAlive = 15 * time.Second
+
+// Stream the events of a game room as Server-Sent Events, for players whose network blocks websockets.
+// Every event is named after the message type and its data is the same message the websocket sends.
+func GameEventsHandler(c *gin.Context) {
+	client, unsubscribe, err := Games.SubscribeGame(c.Param("gameID"), c.Query("sessionId"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Stop nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	keepAlive := time.NewTicker(eventStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	disconnected := c.Request.Context().Done()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-disconnected:
+			return false
+		case message, ok := <-client.Send:
+			if !ok {
+				// The hub removed the client
+				return false
+			}
+			c.SSEvent(message.Type, message)
+			return true
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			return true
+		}
+	})
+}
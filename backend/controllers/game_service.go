@@ -0,0 +1,487 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/models"
+)
+
+// GameService runs games for every transport, the REST handlers and the RPC service are thin adapters around it.
+// Its errors are *APIError or model errors, toAPIError tells what the client is told about them.
+type GameService struct {
+	games     models.GameRepository
+	clock     models.Clock
+	questions models.QuestionSource
+	events    GameEventPublisher
+	stores    GameStores
+	// Samples the questions of games and seeds the random numbers of each game
+	random *rand.Rand
+	// Size of the question bank, request validation checks question counts against it
+	bankSize questionBankSizeCache
+}
+
+type questionBankSizeCache struct {
+	sync.Mutex
+	size    int
+	checked time.Time
+}
+
+// GameStores keep what players did in finished games, and the schedules and attempts that pick their next questions
+type GameStores struct {
+	Leaderboards *models.Leaderboard
+	Ratings      *models.RatingStore
+	Archive      *models.GameArchiveStore
+	Repetitions  *models.RepetitionStore
+	Daily        *models.DailyChallengeStore
+}
+
+// NewGameStores creates empty stores, they are only saved to disk once they are loaded from a file
+func NewGameStores() GameStores {
+	return GameStores{
+		Leaderboards: models.NewLeaderboard(),
+		Ratings:      models.NewRatingStore(),
+		Archive:      models.NewGameArchiveStore(),
+		Repetitions:  models.NewRepetitionStore(),
+		Daily:        models.NewDailyChallengeStore(),
+	}
+}
+
+// SharedGameStores are the global stores the handlers read and setupServer loads
+var SharedGameStores = GameStores{
+	Leaderboards: models.Leaderboards,
+	Ratings:      models.Ratings,
+	Archive:      models.GameArchive,
+	Repetitions:  models.Repetitions,
+	Daily:        models.DailyChallenges,
+}
+
+// NewGameService creates a service, games and their questions are the same every time for the same seed and clock
+func NewGameService(games models.GameRepository, clock models.Clock, seed int64, questions models.QuestionSource, events GameEventPublisher, stores GameStores) *GameService {
+	return &GameService{games: games, clock: clock, questions: questions, events: events, stores: stores, random: models.NewRandom(seed)}
+}
+
+// Games is the service the handlers of every transport use
+var Games = NewGameService(models.GameServerRepository, models.SystemClock, time.Now().UnixNano(), QuestionSources, HubPublisher{}, SharedGameStores)
+
+type StartGameRequest struct {
+	Name        string `json:"name" binding:"playername"`
+	Multiplayer bool   `json:"multiplayer"`
+	// The daily challenge always has the same number of questions
+	Questions  int               `json:"questions" binding:"required_unless=Mode daily,min=0"`
+	Public     bool              `json:"public"`
+	Capacity   int               `json:"capacity" binding:"min=0"`
+	Category   string            `json:"category"`
+	NamePolicy models.NamePolicy `json:"namePolicy" binding:"namepolicy"`
+	Mode       models.GameMode   `json:"mode" binding:"gamemode"`
+	// Set from the identity the server issued to the caller, never from the body
+	PlayerID string `json:"-"`
+	// Uses of each power-up per session, every power-up can be used once when not set
+	PowerUps map[models.PowerUp]int `json:"powerUps" binding:"dive,keys,powerup,endkeys,min=0"`
+	Locale   string                 `json:"locale"`
+}
+
+type StartedGame struct {
+	GameID    string `json:"gameId"`
+	SessionID string `json:"sessionId"`
+	PlayerID  string `json:"playerId"`
+}
+
+type JoinGameRequest struct {
+	GameID   string `json:"gameId" binding:"required"`
+	Name     string `json:"name" binding:"playername"`
+	PlayerID string `json:"-"`
+	Locale   string `json:"locale"`
+}
+
+type JoinedGame struct {
+	GameID    string `json:"gameId"`
+	SessionID string `json:"sessionId"`
+	PlayerID  string `json:"playerId"`
+	// The name given to the player, it can have a suffix when the name is taken
+	Name string `json:"name"`
+}
+
+// GameView is a game as seen by one of its sessions, without the answers
+type GameView struct {
+	ID             string                 `json:"id"`
+	Started        bool                   `json:"started"`
+	Finished       bool                   `json:"finished"`
+	Multiplayer    bool                   `json:"multiplayer"`
+	Mode           models.GameMode        `json:"mode"`
+	Questions      []models.Question      `json:"questions"`
+	QuestionIndex  int                    `json:"questionIndex"`
+	CurrentScore   int                    `json:"currentScore"`
+	PowerUps       map[models.PowerUp]int `json:"powerUps"`
+	DoublePoints   bool                   `json:"doublePoints"`
+	RemovedOptions map[string][]int       `json:"removedOptions"`
+	Locale         string                 `json:"locale"`
+	Owner          bool                   `json:"owner,omitempty"`
+}
+
+type AnswerRequest struct {
+	GameID     string `json:"gameId" binding:"required"`
+	SessionID  string `json:"sessionId" binding:"required"`
+	QuestionID string `json:"questionId" binding:"required"`
+	// Shape depends on the question type, see models.QuestionType
+	Answer json.RawMessage `json:"answer" binding:"required"`
+}
+
+type AnswerResult struct {
+	AlreadyAnswered   bool    `json:"alreadyAnswered"`
+	Correct           bool    `json:"correct"`
+	Credit            float64 `json:"credit"`
+	CurrentScore      int     `json:"currentScore"`
+	NextQuestionIndex int     `json:"nextQuestionIndex"`
+}
+
+type PlayerScore struct {
+	Name      string `json:"name"`
+	SessionID string `json:"sessionId"`
+	Score     int    `json:"score,string"`
+}
+
+// GameEnd is the final score of a session with the scores of the other players
+type GameEnd struct {
+	FinalScore  int                       `json:"finalScore"`
+	Multiplayer bool                      `json:"multiplayer"`
+	Finished    bool                      `json:"finished"`
+	Players     []PlayerScore             `json:"players,omitempty"`
+	Leaderboard []models.LeaderboardEntry `json:"leaderboard"`
+}
+
+type PowerUpRequest struct {
+	GameID    string         `json:"gameId" binding:"required"`
+	SessionID string         `json:"sessionId" binding:"required"`
+	PowerUp   models.PowerUp `json:"powerUp" binding:"required,powerup"`
+}
+
+type PowerUpResult struct {
+	PowerUp           models.PowerUp         `json:"powerUp"`
+	QuestionID        string                 `json:"questionId"`
+	PowerUpsLeft      map[models.PowerUp]int `json:"powerUpsLeft"`
+	DoublePoints      bool                   `json:"doublePoints"`
+	CurrentScore      int                    `json:"currentScore"`
+	NextQuestionIndex int                    `json:"nextQuestionIndex"`
+	// Only set for a 50/50
+	RemovedOptions []int `json:"removedOptions,omitempty"`
+}
+
+// Number of players shown on the leaderboard at the end of a game
+const endGameLeaderboardSize = 10
+
+// StartGame creates a game and the session of its owner
+func (service *GameService) StartGame(request StartGameRequest) (StartedGame, error) {
+	if request.Mode != models.GameModeDaily {
+		if err := service.checkQuestionCount(request.Questions); err != nil {
+			return StartedGame{}, err
+		}
+	}
+
+	playerID := getOrCreatePlayerID(request.PlayerID)
+	settings := models.GameSettings{Public: request.Public, Capacity: request.Capacity, Category: request.Category, NamePolicy: request.NamePolicy, Mode: request.Mode, PowerUps: request.PowerUps}
+	var questions []models.Question
+	var err error
+	switch request.Mode {
+	case models.GameModePractice:
+		// Practice follows the schedule of a single player
+		request.Multiplayer = false
+		questions, err = service.loadPracticeQuestions(playerID, request.Questions, request.Category)
+	case models.GameModeDaily:
+		now := service.clock.Now()
+		request.Multiplayer = false
+		settings.Category = ""
+		settings.Day = models.DailyKey(now)
+		// Everyone gets the same questions, so nobody gets any help
+		settings.PowerUps = map[models.PowerUp]int{}
+		questions, err = service.loadDailyQuestions(now)
+	default:
+		settings.Mode = models.GameModeClassic
+		questions, err = service.LoadQuestions(request.Questions, request.Category)
+	}
+	if err != nil {
+		return StartedGame{}, err
+	}
+
+	if settings.Mode == models.GameModeDaily {
+		// A new ID would be a new attempt, so the daily challenge is only played under an issued identity
+		if request.PlayerID == "" {
+			return StartedGame{}, errDailyNeedsIdentity
+		}
+		err := service.stores.Daily.StartAttempt(settings.Day, playerID, request.Name, service.clock.Now())
+		if errors.Is(err, models.ErrDailyAttemptUsed) {
+			return StartedGame{}, err
+		}
+		if err != nil {
+			// The attempt is still kept in memory, it will be saved with the next change
+			logError(err)
+		}
+	}
+
+	gameServer := service.NewGameServer(questions, models.NewSessionStore(settings.NamePolicy), request.Multiplayer, settings)
+	sessionID, err := gameServer.Sessions.CreateSession(request.Name, playerID)
+	if err != nil {
+		return StartedGame{}, err
+	}
+	gameServer.Owner = sessionID
+	session, _ := gameServer.Sessions.GetSession(sessionID)
+	session.Locale = request.Locale
+	if gameServer.IsJoinable() {
+		service.events.LobbyChanged()
+	}
+	return StartedGame{GameID: gameServer.ID, SessionID: sessionID, PlayerID: playerID}, nil
+}
+
+// JoinGame adds a player to a multiplayer game that has room for them
+func (service *GameService) JoinGame(request JoinGameRequest) (JoinedGame, error) {
+	gameServer, err := service.games.Get(request.GameID)
+	if err != nil {
+		return JoinedGame{}, err
+	}
+
+	// Do not allow joining a single player game
+	if !gameServer.Multiplayer {
+		return JoinedGame{}, errSinglePlayerGame
+	}
+	if !gameServer.Finished.IsZero() {
+		return JoinedGame{}, errGameAlreadyFinished
+	}
+	if gameServer.IsFull() {
+		return JoinedGame{}, errGameFull
+	}
+
+	playerID := getOrCreatePlayerID(request.PlayerID)
+	sessionID, err := gameServer.Sessions.CreateSession(request.Name, playerID)
+	if err != nil {
+		return JoinedGame{}, err
+	}
+
+	session, _ := gameServer.Sessions.GetSession(sessionID)
+	session.Locale = request.Locale
+	service.events.PlayerJoined(gameServer, session)
+	if gameServer.Settings.Public {
+		service.events.LobbyChanged()
+	}
+	return JoinedGame{GameID: gameServer.ID, SessionID: sessionID, PlayerID: playerID, Name: session.Name}, nil
+}
+
+// GetGame returns the game as seen by the session, the questions are in the locale of the session
+func (service *GameService) GetGame(gameID string, sessionID string) (GameView, error) {
+	gameServer, session, err := service.getGameSession(gameID, sessionID)
+	if err != nil {
+		return GameView{}, err
+	}
+
+	return GameView{
+		ID:             gameServer.ID,
+		Started:        !gameServer.Started.IsZero(),
+		Finished:       !gameServer.Finished.IsZero(),
+		Multiplayer:    gameServer.Multiplayer,
+		Mode:           gameServer.Settings.Mode,
+		Questions:      RemoveAnswers(models.LocalizeQuestions(gameServer.Questions, session.Locale)),
+		QuestionIndex:  session.CurrentQuestion,
+		CurrentScore:   session.Score,
+		PowerUps:       session.PowerUpsLeft(gameServer.Settings.PowerUps),
+		DoublePoints:   session.DoublePoints,
+		RemovedOptions: session.RemovedOptions,
+		Locale:         session.Locale,
+		Owner:          gameServer.Owner == sessionID,
+	}, nil
+}
+
+// SubmitAnswer grades an answer, scores it and moves the session on to the next question
+func (service *GameService) SubmitAnswer(request AnswerRequest) (AnswerResult, error) {
+	gameServer, session, err := service.getGameSession(request.GameID, request.SessionID)
+	if err != nil {
+		return AnswerResult{}, err
+	}
+
+	// Answers must stay within what the power-ups left the session to choose from
+	if session.IsSkipped(request.QuestionID) {
+		return AnswerResult{}, errQuestionSkipped
+	}
+	if session.IsOptionRemoved(request.QuestionID, request.Answer) {
+		return AnswerResult{}, errOptionRemoved
+	}
+
+	credit, alreadyAnswered, err := gameServer.CheckAnswer(session.ID, request.QuestionID, request.Answer)
+	if err != nil {
+		return AnswerResult{}, err
+	}
+	correct := credit == 1
+
+	if correct {
+		session.Correct++
+	}
+
+	// Partly correct answers earn part of the points
+	points := int(math.Round(10 * credit))
+	scored := !alreadyAnswered && points > 0
+	if scored {
+		// Double points is used up by the first answer that scores
+		if session.DoublePoints {
+			points *= 2
+			session.DoublePoints = false
+		}
+		session.Score += points
+	}
+
+	session.Answers = append(session.Answers, models.AnswerRecord{
+		QuestionID: request.QuestionID,
+		Answer:     request.Answer,
+		Correct:    correct,
+		Scored:     scored,
+		AnsweredAt: service.clock.Now(),
+	})
+	service.stores.Repetitions.Review(session.PlayerID, request.QuestionID, correct, service.clock.Now())
+
+	service.advanceSession(gameServer, session)
+	service.events.ScoresChanged(gameServer)
+
+	return AnswerResult{
+		AlreadyAnswered:   alreadyAnswered,
+		Correct:           correct,
+		Credit:            credit,
+		CurrentScore:      session.Score,
+		NextQuestionIndex: session.CurrentQuestion,
+	}, nil
+}
+
+// EndGame returns the final score of the session, the game finishes once every player has finished
+func (service *GameService) EndGame(gameID string, sessionID string) (GameEnd, error) {
+	gameServer, session, err := service.getGameSession(gameID, sessionID)
+	if err != nil {
+		return GameEnd{}, err
+	}
+
+	// Check to see if all players have finished
+	allFinished := true
+	for _, session := range gameServer.Sessions.All() {
+		if session.Finished.IsZero() {
+			allFinished = false
+			break
+		}
+	}
+
+	if allFinished {
+		service.finishGameServer(gameServer)
+		service.events.GameFinished(gameServer)
+	} else {
+		service.events.ScoresChanged(gameServer)
+	}
+	return service.getGameEnd(gameServer, session), nil
+}
+
+// UsePowerUp uses a power-up on the current question of the session
+func (service *GameService) UsePowerUp(request PowerUpRequest) (PowerUpResult, error) {
+	gameServer, session, err := service.getGameSession(request.GameID, request.SessionID)
+	if err != nil {
+		return PowerUpResult{}, err
+	}
+
+	if session.CurrentQuestion >= len(gameServer.Questions) {
+		return PowerUpResult{}, errNoQuestionForPowerUp
+	}
+	question := gameServer.Questions[session.CurrentQuestion]
+
+	// Pick the options first so a question that cannot be halved does not cost a use
+	var removedOptions []int
+	if request.PowerUp == models.PowerUpFiftyFifty {
+		removedOptions, err = models.FiftyFiftyOptions(question, gameServer.Random)
+		if err != nil {
+			return PowerUpResult{}, err
+		}
+	}
+
+	if err := session.UsePowerUp(request.PowerUp, gameServer.Settings.PowerUps); err != nil {
+		return PowerUpResult{}, err
+	}
+
+	switch request.PowerUp {
+	case models.PowerUpFiftyFifty:
+		session.RemoveOptions(question.ID, removedOptions)
+	case models.PowerUpSkip:
+		session.Skipped = append(session.Skipped, question.ID)
+		service.advanceSession(gameServer, session)
+	case models.PowerUpDoublePoints:
+		session.DoublePoints = true
+	}
+
+	service.events.PowerUpUsed(gameServer, session, request.PowerUp)
+
+	return PowerUpResult{
+		PowerUp:           request.PowerUp,
+		QuestionID:        question.ID,
+		PowerUpsLeft:      session.PowerUpsLeft(gameServer.Settings.PowerUps),
+		DoublePoints:      session.DoublePoints,
+		CurrentScore:      session.Score,
+		NextQuestionIndex: session.CurrentQuestion,
+		RemovedOptions:    removedOptions,
+	}, nil
+}
+
+// SubscribeGame subscribes to the events of the game room, the first events are the players and their scores.
+// unsubscribe must be called once the stream is over.
+func (service *GameService) SubscribeGame(gameID string, sessionID string) (client *models.StreamClient, unsubscribe func(), err error) {
+	gameServer, err := service.games.Get(gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hub := models.GetOrCreateHub()
+	client = models.NewStreamClient(gameServer.ID, sessionID)
+	hub.Register <- client
+
+	SendExistingPlayersMessage(client, gameServer)
+	SendScoreUpdateMessage(client, gameServer)
+	return client, func() { hub.Unregister <- client }, nil
+}
+
+func (service *GameService) getGameSession(gameID string, sessionID string) (*models.GameServer, *models.PlayerSession, error) {
+	gameServer, err := service.games.Get(gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session, exists := gameServer.Sessions.GetSession(sessionID)
+	if !exists {
+		return nil, nil, errSessionNotFound
+	}
+	return gameServer, session, nil
+}
+
+// Get the details of the game server and the session
+func (service *GameService) getGameEnd(gameServer *models.GameServer, session *models.PlayerSession) GameEnd {
+	gameEnd := GameEnd{
+		FinalScore:  session.Score,
+		Multiplayer: gameServer.Multiplayer,
+		Finished:    !gameServer.Finished.IsZero(),
+		Leaderboard: paginate(service.stores.Leaderboards.Ranked(models.LeaderboardAllTime, models.SortByScore, service.clock.Now(), service.stores.Ratings), endGameLeaderboardSize, 0),
+	}
+
+	if gameServer.Multiplayer {
+		for _, player := range gameServer.Sessions.All() {
+			gameEnd.Players = append(gameEnd.Players, PlayerScore{Name: player.Name, SessionID: player.ID, Score: player.Score})
+		}
+	}
+	return gameEnd
+}
+
+// Moves the session on to the next question and records its result after the last one
+func (service *GameService) advanceSession(gameServer *models.GameServer, session *models.PlayerSession) {
+	session.CurrentQuestion++
+	// Check to see if the current question is the last question and mark finished
+	if session.CurrentQuestion >= len(gameServer.Questions) && session.Finished.IsZero() {
+		session.MarkFinished(gameServer.Clock.Now())
+		service.recordSessionResult(gameServer, session)
+
+		// The schedule is saved once per session instead of after every answer, answers of abandoned sessions are saved with the next one
+		if err := service.stores.Repetitions.Save(); err != nil {
+			logError(err)
+		}
+	}
+}
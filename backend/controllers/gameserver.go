@@ -1,127 +1,114 @@
 package controllers
 
 import (
-	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
-	"time"
 
 	"github.com/ProlificLabs/captrivia/models"
 	"github.com/ProlificLabs/captrivia/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func NewGameServer(questions []models.Question, store *models.SessionStore, multiplayer bool) *models.GameServer {
+// NewGameServer creates a game and saves it in the repository of the service
+func (service *GameService) NewGameServer(questions []models.Question, store *models.SessionStore, multiplayer bool, settings models.GameSettings) *models.GameServer {
+	newGameServer := service.buildGameServer(questions, store, multiplayer, settings)
+	service.games.Save(newGameServer)
+	return newGameServer
+}
+
+// buildGameServer creates a game without saving it, so games built together can be saved once all of them are built
+func (service *GameService) buildGameServer(questions []models.Question, store *models.SessionStore, multiplayer bool, settings models.GameSettings) *models.GameServer {
+	if settings.Capacity <= 0 {
+		settings.Capacity = models.DefaultGameCapacity
+	}
+	if settings.PowerUps == nil {
+		settings.PowerUps = models.DefaultPowerUps()
+	}
+
+	now := service.clock.Now()
 	uniqueGameID := utils.GenerateRandomID()
 	newGameServer := &models.GameServer{
 		Questions: questions,
 		Sessions:  store,
 		ID:        uniqueGameID,
 		Multiplayer: multiplayer,
+		Settings: settings,
+		Created: now,
+		Clock: service.clock,
+		Random: models.NewRandom(service.random.Int63()),
 	}
 
 	// If the game is not multiplayer, start it immediately
 	if !multiplayer {
-		newGameServer.Started = time.Now()
+		newGameServer.Started = now
 	}
 
-	storeGameServer(newGameServer)
 	return newGameServer
 }
 
+// GetGameServer returns a game of the service every transport uses
 func GetGameServer(gameID string) (*models.GameServer, error) {
-	gameServer, exists := models.GameServers[gameID]
-	if !exists {
-		return nil, errors.New("game server not found")
-	}
-	return gameServer, nil
-}
-
-func storeGameServer(gameServer *models.GameServer) {
-	models.GameServers[gameServer.ID] = gameServer
+	return Games.games.Get(gameID)
 }
 
-func markGameServerFinished(gameID string) error {
-	gameServer, exists := models.GameServers[gameID]
-	if !exists {
-		return errors.New("game server not found")
+// Marks the game as finished, archives it and rates multiplayer games, only the first call has any effect and returns true
+func (service *GameService) finishGameServer(gameServer *models.GameServer) bool {
+	if !gameServer.MarkFinished() {
+		return false
 	}
 
-	gameServer.Finished = time.Now()
-	return nil
-}
-
-func GetGameHandler(c *gin.Context) {
-	gameID := c.Param("gameID")
-	sessionID := c.Param("sessionID")
-
-	gameServer, err := GetGameServer(gameID)
-	if err != nil {
-		c.Error(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		return
-	}
-
-	session, exists := gameServer.Sessions.GetSession(sessionID)
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Invalid session ID"})
-		return
+	// The archive and ratings are still updated in memory if saving fails, they will be saved with the next game
+	if err := service.stores.Archive.Archive(gameServer); err != nil {
+		logError(err)
 	}
 
-	questions := RemoveAnswers(gameServer.Questions)
-	response := gin.H{
-		"id":       gameServer.ID,
-		"started": !gameServer.Started.IsZero(),
-		"finished": !gameServer.Finished.IsZero(),
-		"multiplayer": gameServer.Multiplayer,
-		"questions": questions,
-		"questionIndex": session.CurrentQuestion,
-		"currentScore": session.Score,
+	if gameServer.Multiplayer {
+		if err := service.stores.Ratings.RecordGame(gameServer.ID, gameServer.Sessions.All(), gameServer.Finished); err != nil {
+			logError(err)
+		}
 	}
 
-	if gameServer.Owner == sessionID {
-		response["owner"] = true
+	if gameServer.Settings.TournamentID != "" {
+		if err := recordTournamentMatch(gameServer); err != nil {
+			logError(err)
+		}
 	}
-
-	c.JSON(http.StatusOK, response)
+	return true
 }
 
-func JoinGameHandler(c *gin.Context) {
-	var request struct {
-		GameID string `json:"gameId"`
-		Name string `json:"name"`
-	}
-	if err := c.BindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
-		return
+// Callers without a server-issued identity, like tests and tools using the service directly, are given a new ID
+func getOrCreatePlayerID(playerID string) string {
+	if playerID == "" {
+		return utils.GenerateRandomID()
 	}
+	return playerID
+}
 
-	gameServer, err := GetGameServer(request.GameID)
+func GetGameHandler(c *gin.Context) {
+	game, err := Games.GetGame(c.Param("gameID"), c.Param("sessionID"))
 	if err != nil {
-		c.Error(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
-	// Do not allow joining a single player game
-	if !gameServer.Multiplayer {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot join a single player game"})
+	c.JSON(http.StatusOK, game)
+}
+
+func JoinGameHandler(c *gin.Context) {
+	var request JoinGameRequest
+	if !bindJSON(c, &request) {
 		return
 	}
+	request.Locale = sessionLocale(c, request.Locale)
+	request.PlayerID = playerIdentity(c)
 
-	if !gameServer.Finished.IsZero() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Game has already finished"})
+	joined, err := Games.JoinGame(request)
+	if err != nil {
+		abortWithError(c, err)
 		return
 	}
 
-	sessionID := gameServer.Sessions.CreateSession(request.Name)
-	PlayerJoinedNotification(request.GameID, request.Name, sessionID)
-
-	c.JSON(http.StatusOK, gin.H{
-		"gameId":    gameServer.ID,
-		"sessionId": sessionID,
-	})
+	c.JSON(http.StatusOK, joined)
 }
 
 func GameWebSocketHandler(c *gin.Context) {
@@ -129,8 +116,7 @@ func GameWebSocketHandler(c *gin.Context) {
 
 	gameServer, err := GetGameServer(gameID)
 	if err != nil {
-		c.Error(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
@@ -141,6 +127,7 @@ func GameWebSocketHandler(c *gin.Context) {
 	}
 	hub := models.GetOrCreateHub()
 	client := models.NewClient(gameID, socket, hub)
+	client.SessionID = c.Query("sessionId")
 
 	hub.Register <- client
 	go client.Write()
@@ -152,119 +139,36 @@ func GameWebSocketHandler(c *gin.Context) {
 
 // Start a new game
 func StartGameHandler(c *gin.Context) {
-	var request struct {
-		Name string `json:"name"`
-		Multiplayer bool `json:"multiplayer"`
-		Questions int `json:"questions"`
-	}
-	if err := c.BindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	var request StartGameRequest
+	if !bindJSON(c, &request) {
 		return
 	}
+	request.Locale = sessionLocale(c, request.Locale)
+	request.PlayerID = playerIdentity(c)
 
-	questions, err := LoadQuestions(request.Questions)
+	started, err := Games.StartGame(request)
 	if err != nil {
-		c.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
-	gameServer := NewGameServer(questions, &models.SessionStore{Sessions: make(map[string]*models.PlayerSession)}, request.Multiplayer)
-	sessionID := gameServer.Sessions.CreateSession(request.Name)
-	gameServer.Owner = sessionID
-	c.JSON(http.StatusOK, gin.H{
-		"gameId":    gameServer.ID,
-		"sessionId": sessionID,
-	})
-}
-
-func getSessionScoresFromGameServer() map[string]float32 {
-	var playerScores = make(map[string]float32)
-	for _, gameServer := range models.GameServers {
-		for _, session := range gameServer.Sessions.Sessions {
-			// Calculate the score for the player based off the number of questions
-			score := (float32(session.Score / 10) / float32(len(gameServer.Questions))) * 100
-
-
-			currentScore, exists := playerScores[session.Name]
-			if exists {
-				playerScores[session.Name] = (currentScore + score) / 2
-				continue
-			} else {
-				playerScores[session.Name] = score
-			}
-		}
-	}
-
-	return playerScores
-}
-
-// Get the details of the game server and the session
-func getGameEndDetails(gameServer *models.GameServer, session *models.PlayerSession) gin.H {
-	leaderboard := getSessionScoresFromGameServer()
-
-	if gameServer.Multiplayer {
-		sessions := gameServer.Sessions.Sessions
-		existingPlayersContent := make([]map[string]string, 0)
-		for _, player := range sessions {
-			existingPlayerContent := map[string]string{"name": player.Name, "sessionId": player.ID, "score": strconv.Itoa(player.Score)}
-			existingPlayersContent = append(existingPlayersContent, existingPlayerContent)
-		}
-		return gin.H{
-			"finalScore": session.Score,
-			"multiplayer": gameServer.Multiplayer,
-			"finished": !gameServer.Finished.IsZero(),
-			"players": existingPlayersContent,
-			"leaderboard": leaderboard,
-		}
-	}
-	return gin.H{
-		"finalScore": session.Score,
-		"multiplayer": gameServer.Multiplayer,
-		"finished": !gameServer.Finished.IsZero(),
-		"leaderboard": leaderboard,
-	}
+	c.JSON(http.StatusOK, started)
 }
 
 func EndGameHandler(c *gin.Context) {
 	var request struct {
-		SessionID string `json:"sessionId"`
-		GameId string `json:"gameId"`
+		SessionID string `json:"sessionId" binding:"required"`
+		GameId string `json:"gameId" binding:"required"`
 	}
-	if err := c.BindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if !bindJSON(c, &request) {
 		return
 	}
 
-	gameServer, err := GetGameServer(request.GameId)
+	gameEnd, err := Games.EndGame(request.GameId, request.SessionID)
 	if err != nil {
-		c.Error(err)
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		return
-	}
-
-	session, exists := gameServer.Sessions.GetSession(request.SessionID)
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Invalid session ID"})
+		abortWithError(c, err)
 		return
 	}
 
-	// Check to see if all players have finished
-	allFinished := true
-	for _, session := range gameServer.Sessions.Sessions {
-		if session.Finished.IsZero() {
-			allFinished = false
-			break
-		}
-	}
-
-	if allFinished {
-		markGameServerFinished(request.GameId)
-		SendGameFinishedMessage(gameServer)
-		c.JSON(http.StatusOK, getGameEndDetails(gameServer, session))
-		return
-	}
-
-	SendScoreUpdateMessageToAllClients(gameServer)
-	c.JSON(http.StatusOK, getGameEndDetails(gameServer, session))
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, gameEnd)
+}
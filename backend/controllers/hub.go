@@ -16,13 +16,38 @@ func PlayerJoinedNotification(gameServerID string, name string, sessionID string
 	content := map[string]string{"name": name, "sessionId": sessionID}
 	contentValue, _ := json.Marshal(content)
 	for _, client := range clients {
-		client.Send <- models.Message{Type: "playerJoined", Content: string(contentValue)}
+		client.Deliver(models.Message{Type: "playerJoined", Content: string(contentValue)})
+	}
+}
+
+// Sends a message to all clients in the game server to notify them that a player was kicked or banned by the owner
+func PlayerRemovedNotification(gameServerID string, session *models.PlayerSession, banned bool) {
+	hub := models.GetOrCreateHub()
+	clients := hub.GetAllClients(gameServerID)
+
+	content := map[string]string{"name": session.Name, "sessionId": session.ID, "banned": strconv.FormatBool(banned)}
+	contentValue, _ := json.Marshal(content)
+	for _, client := range clients {
+		client.Deliver(models.Message{Type: "playerRemoved", Content: string(contentValue)})
+	}
+}
+
+// Sends a message to all clients in the game server to notify them that a player used a power-up.
+// The options removed by a 50/50 are only given to the player who used it.
+func PowerUpUsedNotification(gameServerID string, session *models.PlayerSession, powerUp models.PowerUp) {
+	hub := models.GetOrCreateHub()
+	clients := hub.GetAllClients(gameServerID)
+
+	content := map[string]string{"name": session.Name, "sessionId": session.ID, "powerUp": string(powerUp)}
+	contentValue, _ := json.Marshal(content)
+	for _, client := range clients {
+		client.Deliver(models.Message{Type: "powerUpUsed", Content: string(contentValue)})
 	}
 }
 
 // Helper function to get the scores of all the players in the game server
 func getPlayerScores(gameServer *models.GameServer) string {
-	existingPlayers := gameServer.Sessions.Sessions
+	existingPlayers := gameServer.Sessions.All()
 	existingPlayersContent := make([]map[string]string, 0)
 	for _, player := range existingPlayers {
 		existingPlayerContent := map[string]string{"name": player.Name, "sessionId": player.ID, "score": strconv.Itoa(player.Score)}
@@ -40,20 +65,20 @@ func SendGameFinishedMessage(gameServer *models.GameServer) {
 
 	playerScores := getPlayerScores(gameServer)
 	for _, client := range clients {
-		client.Send <- models.Message{Type: "gameFinished", Content: playerScores}
+		client.Deliver(models.Message{Type: "gameFinished", Content: playerScores})
 	}
 }
 
 // Sends a message to the newly created client about the existing players in the game
-func SendExistingPlayersMessage(newClient *models.Client, gameServer *models.GameServer) {
+func SendExistingPlayersMessage(newClient models.Subscriber, gameServer *models.GameServer) {
 	playerScores := getPlayerScores(gameServer)
-	newClient.Send <- models.Message{Type: "allPlayers", Content: playerScores}
+	newClient.Deliver(models.Message{Type: "allPlayers", Content: playerScores})
 }
 
 // Sends a message to all clients in the game server to notify them of the current scores
-func SendScoreUpdateMessage(newClient *models.Client, gameServer *models.GameServer) {
+func SendScoreUpdateMessage(newClient models.Subscriber, gameServer *models.GameServer) {
 	playerScores := getPlayerScores(gameServer)
-	newClient.Send <- models.Message{Type: "scoreUpdate", Content: playerScores}
+	newClient.Deliver(models.Message{Type: "scoreUpdate", Content: playerScores})
 }
 
 // Sends a message to all clients in the game server to notify them of the final scores
@@ -63,6 +88,108 @@ func SendScoreUpdateMessageToAllClients(gameServer *models.GameServer) {
 
 	playerScores := getPlayerScores(gameServer)
 	for _, client := range clients {
-		client.Send <- models.Message{Type: "scoreUpdate", Content: playerScores}
+		client.Deliver(models.Message{Type: "scoreUpdate", Content: playerScores})
+	}
+}
+
+// Sends the current list of joinable games to all clients browsing the lobby
+func SendLobbyUpdateMessage() {
+	hub := models.GetOrCreateHub()
+	clients := hub.GetAllClients(models.LobbyRoomID)
+
+	message := models.NewLobbyUpdateMessage()
+	for _, client := range clients {
+		client.Deliver(message)
+	}
+}
+func newQueuePositionMessage(ticket models.Ticket) models.Message {
+	content := map[string]string{"ticketId": ticket.ID, "position": strconv.Itoa(ticket.Position)}
+	contentValue, _ := json.Marshal(content)
+	return models.Message{Type: "queuePosition", ID: ticket.ID, Content: string(contentValue)}
+}
+
+func newMatchFoundMessage(ticket models.Ticket) models.Message {
+	content := map[string]string{"ticketId": ticket.ID, "gameId": ticket.GameID, "sessionId": ticket.SessionID, "playerId": ticket.PlayerID}
+	contentValue, _ := json.Marshal(content)
+	return models.Message{Type: "matchFound", ID: ticket.ID, Content: string(contentValue)}
+}
+
+// Sends a message to the clients of a matchmaking ticket with its new position in the queue
+func SendQueuePositionMessage(ticket models.Ticket) {
+	hub := models.GetOrCreateHub()
+	clients := hub.GetAllClients(ticket.ID)
+
+	message := newQueuePositionMessage(ticket)
+	for _, client := range clients {
+		client.Deliver(message)
 	}
-}
\ No newline at end of file
+}
+
+// Sends a message to the clients of a matchmaking ticket with the game they were moved into
+func SendMatchFoundMessage(ticket models.Ticket) {
+	hub := models.GetOrCreateHub()
+	clients := hub.GetAllClients(ticket.ID)
+
+	message := newMatchFoundMessage(ticket)
+	for _, client := range clients {
+		client.Deliver(message)
+	}
+}
+
+// Sends a message to the clients of a matchmaking ticket that left the queue without a game, with the status of the ticket
+func SendTicketClosedMessage(ticket models.Ticket, messageType string) {
+	content, _ := json.Marshal(getTicketStatus(ticket))
+	message := models.Message{Type: messageType, ID: ticket.ID, Content: string(content)}
+
+	hub := models.GetOrCreateHub()
+	for _, client := range hub.GetAllClients(ticket.ID) {
+		client.Deliver(message)
+	}
+}
+
+// Sends the bracket state to all clients following a tournament
+func SendTournamentUpdateMessage(tournament *models.Tournament) {
+	content, err := tournament.JSON()
+	if err != nil {
+		return
+	}
+
+	hub := models.GetOrCreateHub()
+	clients := hub.GetAllClients(tournament.ID)
+	for _, client := range clients {
+		client.Deliver(models.Message{Type: "tournamentUpdate", ID: tournament.ID, Content: string(content)})
+	}
+}
+
+// GameEventPublisher tells the players of a game, and the lobby, what happened in the game
+type GameEventPublisher interface {
+	PlayerJoined(gameServer *models.GameServer, session *models.PlayerSession)
+	PowerUpUsed(gameServer *models.GameServer, session *models.PlayerSession, powerUp models.PowerUp)
+	ScoresChanged(gameServer *models.GameServer)
+	GameFinished(gameServer *models.GameServer)
+	// The list of joinable games changed
+	LobbyChanged()
+}
+
+// HubPublisher sends the events to the clients of the game rooms and the lobby in the hub
+type HubPublisher struct{}
+
+func (HubPublisher) PlayerJoined(gameServer *models.GameServer, session *models.PlayerSession) {
+	PlayerJoinedNotification(gameServer.ID, session.Name, session.ID)
+}
+
+func (HubPublisher) PowerUpUsed(gameServer *models.GameServer, session *models.PlayerSession, powerUp models.PowerUp) {
+	PowerUpUsedNotification(gameServer.ID, session, powerUp)
+}
+
+func (HubPublisher) ScoresChanged(gameServer *models.GameServer) {
+	SendScoreUpdateMessageToAllClients(gameServer)
+}
+
+func (HubPublisher) GameFinished(gameServer *models.GameServer) {
+	SendGameFinishedMessage(gameServer)
+}
+
+func (HubPublisher) LobbyChanged() {
+	SendLobbyUpdateMessage()
+}
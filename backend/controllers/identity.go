@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ProlificLabs/captrivia/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Cookie holding the signed player ID, scripts cannot read it
+	playerCookieName = "captrivia_player"
+	// Seconds a player keeps their identity without playing
+	playerCookieMaxAge = 365 * 24 * 60 * 60
+)
+
+// playerIdentity returns the player ID the server issued to the caller, a new player is given one in a cookie
+func playerIdentity(c *gin.Context) string {
+	playerID, cookie := requestPlayerIdentity(c.Request.Header, c.Request.TLS != nil)
+	if cookie != nil {
+		http.SetCookie(c.Writer, cookie)
+	}
+	return playerID
+}
+
+// requestPlayerIdentity verifies the identity cookie in the headers of a request, the cookie is only returned when a new ID was issued
+func requestPlayerIdentity(header http.Header, secure bool) (string, *http.Cookie) {
+	request := http.Request{Header: header}
+	if cookie, err := request.Cookie(playerCookieName); err == nil {
+		if playerID, valid := models.PlayerIdentities.Verify(cookie.Value); valid {
+			return playerID, nil
+		}
+	}
+
+	playerID, token := models.PlayerIdentities.Issue()
+	cookie := &http.Cookie{
+		Name:     playerCookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   playerCookieMaxAge,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	// Over HTTPS the frontend can be served from another site, the cookie has to be sent with its requests
+	if secure || header.Get("X-Forwarded-Proto") == "https" {
+		cookie.SameSite = http.SameSiteNoneMode
+		cookie.Secure = true
+	}
+	return playerID, cookie
+}
+
+// Get the player ID of the caller, it is the ID their stats and ratings are kept under
+func CurrentPlayerHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"playerId": playerIdentity(c)})
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/i18n"
+	"github.com/ProlificLabs/captrivia/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Records the result of a session that has answered every question, skipped questions are not counted as answered
+func (service *GameService) recordSessionResult(gameServer *models.GameServer, session *models.PlayerSession) {
+	// Practice games pick the questions the player struggles with, so they are kept off the leaderboard
+	if gameServer.Settings.Mode == models.GameModePractice {
+		return
+	}
+
+	result := models.GameResult{
+		PlayerID: session.PlayerID,
+		Name:     session.Name,
+		Score:    session.Score,
+		Correct:  session.Correct,
+		Answered: len(session.Answers),
+	}
+	// The results are still kept in memory if saving fails, they will be saved with the next one
+	if err := service.stores.Leaderboards.Record(result, session.Finished); err != nil {
+		logError(err)
+	}
+
+	if gameServer.Settings.Mode == models.GameModeDaily {
+		if err := service.stores.Daily.RecordResult(gameServer.Settings.Day, session.PlayerID, result, session.Finished); err != nil {
+			logError(err)
+		}
+	}
+}
+
+// Get a page of the global leaderboard, including the rank of the player in the identity cookie
+func LeaderboardHandler(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	window := models.LeaderboardWindow(c.DefaultQuery("window", string(models.LeaderboardAllTime)))
+	if !window.IsValid() {
+		abortWithError(c, invalidParameter("window", i18n.InvalidWindow))
+		return
+	}
+
+	sortBy := models.LeaderboardSort(c.DefaultQuery("sort", string(models.SortByScore)))
+	if !sortBy.IsValid() {
+		abortWithError(c, invalidParameter("sort", i18n.InvalidSort))
+		return
+	}
+
+	entries := Games.stores.Leaderboards.Ranked(window, sortBy, Games.clock.Now(), Games.stores.Ratings)
+	response := gin.H{
+		"window":  window,
+		"sort":    sortBy,
+		"entries": paginate(entries, limit, offset),
+		"total":   len(entries),
+		"limit":   limit,
+		"offset":  offset,
+	}
+
+	playerID := playerIdentity(c)
+	for _, entry := range entries {
+		if entry.PlayerID == playerID {
+			response["player"] = entry
+			break
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Get the skill rating of a player along with the change from every rated game
+func RatingHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, Games.stores.Ratings.Get(c.Param("playerID")))
+}
+
+// Get a page of the daily challenge leaderboard, today's unless a date is given
+func DailyLeaderboardHandler(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	day := c.DefaultQuery("date", models.DailyKey(Games.clock.Now()))
+	if _, err := time.Parse("2006-01-02", day); err != nil {
+		abortWithError(c, invalidParameter("date", i18n.InvalidDate))
+		return
+	}
+
+	entries := Games.stores.Daily.Leaderboard(day)
+	c.JSON(http.StatusOK, gin.H{
+		"date":    day,
+		"entries": paginate(entries, limit, offset),
+		"total":   len(entries),
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ProlificLabs/captrivia/models"
+	"github.com/ProlificLabs/captrivia/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// List the public multiplayer games that can still be joined
+func ListGamesHandler(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	questions, err := parseQueryInt(c, "questions", 0)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	lobbies := models.ListLobbies(models.LobbyFilter{Category: c.Query("category"), Questions: questions})
+	c.JSON(http.StatusOK, gin.H{
+		"games":  paginate(lobbies, limit, offset),
+		"total":  len(lobbies),
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// Subscribe to changes of the public game list
+func LobbyWebSocketHandler(c *gin.Context) {
+	socket, err := utils.UpgradeToWebSocket(c.Writer, c.Request)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	hub := models.GetOrCreateHub()
+	client := models.NewClient(models.LobbyRoomID, socket, hub)
+
+	hub.Register <- client
+	go client.Write()
+	go client.Read()
+
+	client.Deliver(models.NewLobbyUpdateMessage())
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/ProlificLabs/captrivia/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// requestLocale picks the locale of a request from the locale query parameter,
+// then the preferred language of the Accept-Language header
+func requestLocale(c *gin.Context) string {
+	if locale := c.Query("locale"); locale != "" {
+		return i18n.Normalize(locale)
+	}
+
+	return headerLocale(c.GetHeader("Accept-Language"))
+}
+
+// headerLocale returns the preferred language of an Accept-Language header
+func headerLocale(acceptLanguage string) string {
+	preferred, _, _ := strings.Cut(acceptLanguage, ",")
+	preferred, _, _ = strings.Cut(preferred, ";")
+	if preferred = i18n.Normalize(preferred); preferred != "" && preferred != "*" {
+		return preferred
+	}
+	return i18n.DefaultLocale
+}
+
+// message returns the catalog message in the locale of the request
+func message(c *gin.Context, key i18n.Key) string {
+	return i18n.Message(requestLocale(c), key)
+}
+
+// sessionLocale picks the locale a new session sees the questions in, the one asked for in the body wins
+func sessionLocale(c *gin.Context, requested string) string {
+	if requested != "" {
+		return i18n.Normalize(requested)
+	}
+	return requestLocale(c)
+}
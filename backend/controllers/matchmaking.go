@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/models"
+	"github.com/ProlificLabs/captrivia/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Number of questions in a quick match when the player does not choose
+const defaultMatchQuestions = 10
+
+var startMatchmakerOnce sync.Once
+
+// startMatchmaker starts the matchmaker goroutine the first time a player joins the queue
+func startMatchmaker() {
+	startMatchmakerOnce.Do(func() {
+		go runMatchmaker()
+	})
+}
+
+func runMatchmaker() {
+	ticker := Games.clock.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for now := range ticker.C() {
+		matchPlayers(now)
+	}
+}
+
+// matchPlayers moves every group of waiting players that is ready into a new game
+// and tells the players left in the queue about their new position
+func matchPlayers(now time.Time) {
+	for _, tickets := range models.Matchmaking.TakeMatches(now) {
+		createMatch(tickets)
+	}
+
+	// Expired tickets are gone before positions are updated, the players behind them move up
+	for _, ticket := range models.Matchmaking.RemoveExpired(now) {
+		SendTicketClosedMessage(ticket, "ticketExpired")
+	}
+
+	for _, ticket := range models.Matchmaking.UpdatePositions() {
+		SendQueuePositionMessage(ticket)
+	}
+}
+
+// createMatch starts a multiplayer game for the tickets, the first player becomes the owner.
+// Tickets no game can be made for are marked failed, if too few players are left the others go back in line.
+func createMatch(tickets []models.Ticket) {
+	preferences := tickets[0].Preferences
+	questions, err := Games.LoadQuestions(preferences.Questions, preferences.Category)
+	if err != nil {
+		failTickets(tickets, err)
+		return
+	}
+
+	settings := models.GameSettings{Category: preferences.Category, NamePolicy: models.NamePolicySuffix, Mode: models.GameModeClassic}
+	store := models.NewSessionStore(settings.NamePolicy)
+	matched := make([]models.Ticket, 0, len(tickets))
+	for _, ticket := range tickets {
+		sessionID, err := store.CreateSession(ticket.Name, ticket.PlayerID)
+		if err != nil {
+			failTickets([]models.Ticket{ticket}, err)
+			continue
+		}
+		ticket.SessionID = sessionID
+		matched = append(matched, ticket)
+	}
+
+	if len(matched) < models.MatchMinPlayers {
+		models.Matchmaking.Requeue(matched)
+		return
+	}
+
+	gameServer := Games.NewGameServer(questions, store, true, settings)
+	gameServer.Owner = matched[0].SessionID
+	for _, ticket := range matched {
+		models.Matchmaking.MarkMatched(ticket.ID, gameServer.ID, ticket.SessionID, Games.clock.Now())
+		ticket.GameID = gameServer.ID
+		SendMatchFoundMessage(ticket)
+	}
+}
+
+// failTickets tells the players of tickets taken from the queue that no game could be made for them
+func failTickets(tickets []models.Ticket, err error) {
+	logError(err)
+	for _, ticket := range tickets {
+		models.Matchmaking.MarkFailed(ticket.ID, err, Games.clock.Now())
+		ticket.Failure = err
+		SendTicketClosedMessage(ticket, "matchFailed")
+	}
+}
+
+// Public view of a ticket
+func getTicketStatus(ticket models.Ticket) gin.H {
+	if ticket.Failure != nil {
+		return gin.H{
+			"ticketId": ticket.ID,
+			"status":   "failed",
+			"code":     toAPIError(ticket.Failure).Code,
+			"playerId": ticket.PlayerID,
+		}
+	}
+	if ticket.GameID != "" {
+		return gin.H{
+			"ticketId":  ticket.ID,
+			"status":    "matched",
+			"gameId":    ticket.GameID,
+			"sessionId": ticket.SessionID,
+			"playerId":  ticket.PlayerID,
+		}
+	}
+	return gin.H{
+		"ticketId": ticket.ID,
+		"status":   "waiting",
+		"position": ticket.Position,
+		"playerId": ticket.PlayerID,
+	}
+}
+
+// Put a player in the quick-match queue for their preferences
+func EnqueueHandler(c *gin.Context) {
+	var request struct {
+		Name      string `json:"name" binding:"playername"`
+		Questions int    `json:"questions" binding:"min=0"`
+		Category  string `json:"category"`
+	}
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	if request.Questions == 0 {
+		request.Questions = defaultMatchQuestions
+	}
+
+	// Make sure a game can be created with these preferences before the player starts waiting
+	if err := Games.checkQuestionCount(request.Questions); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	if _, err := Games.LoadQuestions(request.Questions, request.Category); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	playerID := playerIdentity(c)
+	preferences := models.MatchPreferences{
+		Questions:  request.Questions,
+		Category:   request.Category,
+		RatingBand: models.RatingBand(Games.stores.Ratings.RatingOf(playerID)),
+	}
+	ticket, replaced := models.Matchmaking.Enqueue(playerID, request.Name, preferences, Games.clock.Now())
+	for _, replacedTicket := range replaced {
+		SendTicketClosedMessage(replacedTicket, "ticketReplaced")
+	}
+	startMatchmaker()
+
+	c.JSON(http.StatusOK, getTicketStatus(ticket))
+}
+
+// Leave the quick-match queue
+func CancelMatchmakingHandler(c *gin.Context) {
+	var request struct {
+		TicketID string `json:"ticketId" binding:"required"`
+	}
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	if err := models.Matchmaking.Cancel(request.TicketID); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ticketId": request.TicketID, "status": "cancelled"})
+}
+
+// Get the queue position of a ticket, or the game it was matched into
+func MatchmakingStatusHandler(c *gin.Context) {
+	ticket, exists := models.Matchmaking.GetTicket(c.Param("ticketID"))
+	if !exists {
+		abortWithError(c, models.ErrTicketNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, getTicketStatus(ticket))
+}
+
+// Subscribe to queue position updates and the match notification of a ticket
+func MatchmakingWebSocketHandler(c *gin.Context) {
+	ticketID := c.Param("ticketID")
+	if _, exists := models.Matchmaking.GetTicket(ticketID); !exists {
+		abortWithError(c, models.ErrTicketNotFound)
+		return
+	}
+
+	socket, err := utils.UpgradeToWebSocket(c.Writer, c.Request)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	hub := models.GetOrCreateHub()
+	client := models.NewClient(ticketID, socket, hub)
+
+	hub.Register <- client
+	go client.Write()
+	go client.Read()
+
+	// The match may have been made before the client connected
+	ticket, exists := models.Matchmaking.GetTicket(ticketID)
+	if !exists {
+		return
+	}
+	if ticket.GameID != "" {
+		client.Deliver(newMatchFoundMessage(ticket))
+		return
+	}
+	client.Deliver(newQueuePositionMessage(ticket))
+}
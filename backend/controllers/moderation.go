@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ProlificLabs/captrivia/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Kick a player out of the game, they are free to join again
+func KickPlayerHandler(c *gin.Context) {
+	removePlayer(c, false)
+}
+
+// Kick a player out of the game and prevent them from joining again
+func BanPlayerHandler(c *gin.Context) {
+	removePlayer(c, true)
+}
+
+func removePlayer(c *gin.Context, ban bool) {
+	var request struct {
+		GameID          string `json:"gameId" binding:"required"`
+		SessionID       string `json:"sessionId" binding:"required"`
+		TargetSessionID string `json:"targetSessionId" binding:"required"`
+	}
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	gameServer, err := GetGameServer(request.GameID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Only the owner of the game can moderate it
+	if gameServer.Owner != request.SessionID {
+		abortWithError(c, errNotGameOwner)
+		return
+	}
+
+	if request.TargetSessionID == gameServer.Owner {
+		abortWithError(c, errOwnerCannotBeRemoved)
+		return
+	}
+
+	target, exists := gameServer.Sessions.GetSession(request.TargetSessionID)
+	if !exists {
+		abortWithError(c, errSessionNotFound)
+		return
+	}
+
+	if ban {
+		gameServer.Sessions.Ban(target.PlayerID)
+	}
+	gameServer.Sessions.DeleteSession(target.ID)
+	models.GetOrCreateHub().RemoveSessionClients(gameServer.ID, target.ID)
+
+	PlayerRemovedNotification(gameServer.ID, target, ban)
+	SendScoreUpdateMessageToAllClients(gameServer)
+	if gameServer.IsJoinable() {
+		SendLobbyUpdateMessage()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessionId": target.ID,
+		"banned":    ban,
+	})
+}
@@ -0,0 +1,13 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ProlificLabs/captrivia/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// Serve the OpenAPI specification of the API
+func OpenAPIHandler(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", openapi.Spec)
+}
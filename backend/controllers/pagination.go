@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/ProlificLabs/captrivia/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// parseQueryInt reads an optional non-negative integer query parameter
+func parseQueryInt(c *gin.Context, key string, fallback int) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return 0, invalidParameter(key, i18n.InvalidParameter)
+	}
+	return parsed, nil
+}
+
+// parsePagination reads the limit and offset query parameters
+func parsePagination(c *gin.Context) (int, int, error) {
+	limit, err := parseQueryInt(c, "limit", defaultPageSize)
+	if err != nil {
+		return 0, 0, err
+	}
+	if limit == 0 || limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	offset, err := parseQueryInt(c, "offset", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	return limit, offset, nil
+}
+
+// paginate returns the page of items selected by limit and offset
+func paginate[T any](items []T, limit int, offset int) []T {
+	if offset > len(items) {
+		offset = len(items)
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
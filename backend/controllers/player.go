@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ProlificLabs/captrivia/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Get the totals and accuracy trend of a player from their finished games
+func PlayerProfileHandler(c *gin.Context) {
+	profile, exists := Games.stores.Archive.Profile(c.Param("playerID"), Games.stores.Ratings)
+	if !exists {
+		abortWithError(c, errPlayerNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// Get a page of the finished games of a player, newest first.
+// Only the player sees the answers of today's daily challenge.
+func PlayerGamesHandler(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	playerID := c.Param("playerID")
+	games := Games.stores.Archive.GamesOfPlayer(playerID)
+	if playerIdentity(c) != playerID {
+		today := models.DailyKey(Games.clock.Now())
+		for i := range games {
+			games[i].HideAnswers(today)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"games":  paginate(games, limit, offset),
+		"total":  len(games),
+		"limit":  limit,
+		"offset": offset,
+	})
+}
@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Use a power-up on the current question of the session
+func UsePowerUpHandler(c *gin.Context) {
+	var request PowerUpRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	result, err := Games.UsePowerUp(request)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -2,15 +2,27 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/rand"
 	"os"
+	"sort"
 	"time"
 
+	"github.com/ProlificLabs/captrivia/generator"
+	"github.com/ProlificLabs/captrivia/i18n"
 	"github.com/ProlificLabs/captrivia/models"
 )
 
-func LoadQuestions(limit int) ([]models.Question, error) {
-	fileBytes, err := os.ReadFile("questions.json")
+var ErrCategoryNotFound = errors.New("no questions found for category")
+
+// fileQuestionSource reads the question bank from a JSON file
+type fileQuestionSource struct {
+	path string
+}
+
+func (source fileQuestionSource) Questions(category string) ([]models.Question, error) {
+	fileBytes, err := os.ReadFile(source.path)
 	if err != nil {
 		return nil, err
 	}
@@ -20,12 +32,138 @@ func LoadQuestions(limit int) ([]models.Question, error) {
 		return nil, err
 	}
 
-	return shuffleQuestions(questions[:limit]), nil
+	// A question that cannot be graded would break every game it ends up in
+	for _, question := range questions {
+		if err := question.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if category != "" {
+		questions = filterQuestionsByCategory(questions, category)
+	}
+	return questions, nil
+}
+
+// QuestionSources provide the questions of every game
+var QuestionSources = QuestionBank{
+	fileQuestionSource{path: "questions.json"},
+	generator.NewSource(),
+}
+
+// QuestionBank collects the questions of every source, each one adds the questions it has for the category
+type QuestionBank []models.QuestionSource
+
+// Questions returns ErrCategoryNotFound when no source has questions for the category
+func (bank QuestionBank) Questions(category string) ([]models.Question, error) {
+	questions := make([]models.Question, 0)
+	for _, source := range bank {
+		sourceQuestions, err := source.Questions(category)
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, sourceQuestions...)
+	}
+
+	if category != "" && len(questions) == 0 {
+		return nil, fmt.Errorf("%w %s", ErrCategoryNotFound, category)
+	}
+	return questions, nil
+}
+
+// How long the size of the question bank is kept before the sources are read again
+const questionBankSizeTTL = time.Minute
+
+// questionBankSize returns the number of questions a game can be built from. Validating a request
+// does not read the sources, they are only read again once the size is older than questionBankSizeTTL
+func (service *GameService) questionBankSize() int {
+	service.bankSize.Lock()
+	defer service.bankSize.Unlock()
+
+	now := service.clock.Now()
+	if !service.bankSize.checked.IsZero() && now.Sub(service.bankSize.checked) < questionBankSizeTTL {
+		return service.bankSize.size
+	}
+
+	questions, err := service.questions.Questions("")
+	if err != nil {
+		// Not kept, so the next request reads the sources again
+		return 0
+	}
+	service.bankSize.size, service.bankSize.checked = len(questions), now
+	return service.bankSize.size
+}
+
+// checkQuestionCount fails validation of a number of questions larger than the question bank of the service
+func (service *GameService) checkQuestionCount(count int) error {
+	if size := service.questionBankSize(); count > size {
+		return errValidationFailed.WithFields(fieldErrors{
+			"questions": {key: i18n.FieldQuestionCount, args: []interface{}{size}},
+		})
+	}
+	return nil
+}
+
+func (service *GameService) LoadQuestions(limit int, category string) ([]models.Question, error) {
+	questions, err := service.questions.Questions(category)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > len(questions) {
+		limit = len(questions)
+	}
+
+	// Shuffle the whole bank before taking the first questions, so every question can be picked
+	return shuffleQuestions(questions, service.random)[:limit], nil
+}
+
+// loadDailyQuestions returns the questions of the daily challenge. Every player gets the same
+// questions in the same order on a given day because the shuffle is seeded from the date.
+func (service *GameService) loadDailyQuestions(day time.Time) ([]models.Question, error) {
+	questions, err := service.questions.Questions("")
+	if err != nil {
+		return nil, err
+	}
+
+	// Do not depend on the order of the question bank file
+	sort.Slice(questions, func(i, j int) bool {
+		return questions[i].ID < questions[j].ID
+	})
+
+	day = day.UTC()
+	seed := int64(day.Year()*10000 + int(day.Month())*100 + day.Day())
+	questions = shuffleQuestions(questions, rand.New(rand.NewSource(seed)))
+
+	limit := models.DailyChallengeQuestions
+	if limit > len(questions) {
+		limit = len(questions)
+	}
+	return questions[:limit], nil
+}
+
+// loadPracticeQuestions picks the questions the player should review next from their spaced-repetition schedule
+func (service *GameService) loadPracticeQuestions(playerID string, limit int, category string) ([]models.Question, error) {
+	questions, err := service.questions.Questions(category)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.stores.Repetitions.Schedule(playerID, questions, limit, service.clock.Now()), nil
+}
+
+func filterQuestionsByCategory(questions []models.Question, category string) []models.Question {
+	filtered := make([]models.Question, 0)
+	for _, question := range questions {
+		if question.Category == category {
+			filtered = append(filtered, question)
+		}
+	}
+	return filtered
 }
 
-func shuffleQuestions(questions []models.Question) []models.Question {
-	rand.New(rand.NewSource(time.Now().UnixNano()))
-	rand.Shuffle(len(questions), func(i, j int) {
+func shuffleQuestions(questions []models.Question, random *rand.Rand) []models.Question {
+	random.Shuffle(len(questions), func(i, j int) {
 		questions[i], questions[j] = questions[j], questions[i]
 	})
 	return questions
@@ -35,9 +173,9 @@ func RemoveAnswers(questions []models.Question) []models.Question {
 	qs := make([]models.Question, len(questions))
 
 	// Copy the questions manually, instead of with copy(), so that we can remove
-	// the CorrectIndex property
+	// the CorrectIndex property and the correct answers of the other question types
 	for i, q := range questions {
-		qs[i] = models.Question{ID: q.ID, QuestionText: q.QuestionText, Options: q.Options}
+		qs[i] = models.Question{ID: q.ID, Category: q.Category, QuestionText: q.QuestionText, Options: q.Options, Type: q.Kind(), Tolerance: q.Tolerance}
 	}
 
 	return qs
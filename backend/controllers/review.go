@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ProlificLabs/captrivia/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Get every question of a finished game with the player's answer, the correct answer and its explanation
+func GameReviewHandler(c *gin.Context) {
+	gameServer, err := GetGameServer(c.Param("gameID"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	session, exists := gameServer.Sessions.GetSession(c.Param("sessionID"))
+	if !exists {
+		abortWithError(c, errSessionNotFound)
+		return
+	}
+
+	// Reviewing reveals the answers, so wait until the player cannot answer anymore
+	if session.Finished.IsZero() && gameServer.Finished.IsZero() {
+		abortWithError(c, errReviewNotAvailable)
+		return
+	}
+
+	answers := make(map[string]models.AnswerRecord, len(session.Answers))
+	for _, answer := range session.Answers {
+		if _, exists := answers[answer.QuestionID]; !exists {
+			answers[answer.QuestionID] = answer
+		}
+	}
+
+	questions := make([]gin.H, 0, len(gameServer.Questions))
+	for _, question := range models.LocalizeQuestions(gameServer.Questions, session.Locale) {
+		review := gin.H{
+			"id":            question.ID,
+			"questionText":  question.QuestionText,
+			"options":       question.Options,
+			"type":          question.Kind(),
+			"correctIndex":  question.CorrectIndex,
+			"correctAnswer": question.Solution(),
+			"explanation":   question.Explanation,
+			"answer":        nil,
+			"correct":       false,
+			"skipped":       session.IsSkipped(question.ID),
+			"claimedBy":     nil,
+		}
+
+		if answer, answered := answers[question.ID]; answered {
+			review["answer"] = answer.Answer
+			review["correct"] = answer.Correct
+		}
+
+		// The session that answered correctly first and got the points
+		if question.CorrectSession != "" {
+			claimedBy := gin.H{"sessionId": question.CorrectSession, "name": ""}
+			if claimer, exists := gameServer.Sessions.GetSession(question.CorrectSession); exists {
+				claimedBy["name"] = claimer.Name
+			}
+			review["claimedBy"] = claimedBy
+		}
+
+		questions = append(questions, review)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"gameId":      gameServer.ID,
+		"sessionId":   session.ID,
+		"multiplayer": gameServer.Multiplayer,
+		"finished":    !gameServer.Finished.IsZero(),
+		"finalScore":  session.Score,
+		"questions":   questions,
+	})
+}
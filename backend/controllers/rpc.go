@@ -0,0 +1,324 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	captriviav1 "github.com/ProlificLabs/captrivia/gen/captrivia/v1"
+	"github.com/ProlificLabs/captrivia/i18n"
+	"github.com/ProlificLabs/captrivia/models"
+)
+
+// GameRPCService serves the GameService over Connect, gRPC and gRPC-Web. Like the REST handlers it only
+// converts messages, errors carry the same code as REST responses in the Error-Code metadata.
+type GameRPCService struct{}
+
+func NewGameRPCService() *GameRPCService {
+	return &GameRPCService{}
+}
+
+func (service *GameRPCService) StartGame(ctx context.Context, req *connect.Request[captriviav1.StartGameRequest]) (*connect.Response[captriviav1.StartGameResponse], error) {
+	message := req.Msg
+	playerID, cookie := requestPlayerIdentity(req.Header(), false)
+	request := StartGameRequest{
+		Name:        message.Name,
+		Multiplayer: message.Multiplayer,
+		Questions:   int(message.Questions),
+		Public:      message.Public,
+		Capacity:    int(message.Capacity),
+		Category:    message.Category,
+		NamePolicy:  models.NamePolicy(message.NamePolicy),
+		Mode:        models.GameMode(message.Mode),
+		Locale:      rpcLocale(req.Header(), message.Locale),
+		PlayerID:    playerID,
+	}
+	// Empty maps cannot be told apart from missing ones, so they get the default power-ups
+	if len(message.PowerUps) > 0 {
+		request.PowerUps = make(map[models.PowerUp]int, len(message.PowerUps))
+		for powerUp, uses := range message.PowerUps {
+			request.PowerUps[models.PowerUp(powerUp)] = int(uses)
+		}
+	}
+	if err := validateRequest(&request); err != nil {
+		return nil, rpcError(req.Header(), err)
+	}
+
+	started, err := Games.StartGame(request)
+	if err != nil {
+		return nil, rpcError(req.Header(), err)
+	}
+	return withPlayerCookie(connect.NewResponse(&captriviav1.StartGameResponse{GameId: started.GameID, SessionId: started.SessionID, PlayerId: started.PlayerID}), cookie), nil
+}
+
+func (service *GameRPCService) JoinGame(ctx context.Context, req *connect.Request[captriviav1.JoinGameRequest]) (*connect.Response[captriviav1.JoinGameResponse], error) {
+	playerID, cookie := requestPlayerIdentity(req.Header(), false)
+	request := JoinGameRequest{
+		GameID:   req.Msg.GameId,
+		Name:     req.Msg.Name,
+		PlayerID: playerID,
+		Locale:   rpcLocale(req.Header(), req.Msg.Locale),
+	}
+	if err := validateRequest(&request); err != nil {
+		return nil, rpcError(req.Header(), err)
+	}
+
+	joined, err := Games.JoinGame(request)
+	if err != nil {
+		return nil, rpcError(req.Header(), err)
+	}
+	return withPlayerCookie(connect.NewResponse(&captriviav1.JoinGameResponse{GameId: joined.GameID, SessionId: joined.SessionID, PlayerId: joined.PlayerID, Name: joined.Name}), cookie), nil
+}
+
+func (service *GameRPCService) GetGame(ctx context.Context, req *connect.Request[captriviav1.GetGameRequest]) (*connect.Response[captriviav1.GetGameResponse], error) {
+	game, err := Games.GetGame(req.Msg.GameId, req.Msg.SessionId)
+	if err != nil {
+		return nil, rpcError(req.Header(), err)
+	}
+
+	response := &captriviav1.GetGameResponse{
+		Id:             game.ID,
+		Started:        game.Started,
+		Finished:       game.Finished,
+		Multiplayer:    game.Multiplayer,
+		Mode:           string(game.Mode),
+		QuestionIndex:  int32(game.QuestionIndex),
+		CurrentScore:   int32(game.CurrentScore),
+		PowerUps:       make(map[string]int32, len(game.PowerUps)),
+		DoublePoints:   game.DoublePoints,
+		RemovedOptions: make(map[string]*captriviav1.OptionIndexes, len(game.RemovedOptions)),
+		Locale:         game.Locale,
+		Owner:          game.Owner,
+	}
+	for _, question := range game.Questions {
+		response.Questions = append(response.Questions, &captriviav1.Question{
+			Id:           question.ID,
+			Category:     question.Category,
+			QuestionText: question.QuestionText,
+			Options:      question.Options,
+			Type:         string(question.Type),
+			Tolerance:    question.Tolerance,
+		})
+	}
+	for powerUp, uses := range game.PowerUps {
+		response.PowerUps[string(powerUp)] = int32(uses)
+	}
+	for questionID, options := range game.RemovedOptions {
+		response.RemovedOptions[questionID] = optionIndexes(options)
+	}
+	return connect.NewResponse(response), nil
+}
+
+func (service *GameRPCService) SubmitAnswer(ctx context.Context, req *connect.Request[captriviav1.SubmitAnswerRequest]) (*connect.Response[captriviav1.SubmitAnswerResponse], error) {
+	request := AnswerRequest{
+		GameID:     req.Msg.GameId,
+		SessionID:  req.Msg.SessionId,
+		QuestionID: req.Msg.QuestionId,
+		Answer:     answerJSON(req.Msg.Answer),
+	}
+	if err := validateRequest(&request); err != nil {
+		return nil, rpcError(req.Header(), err)
+	}
+
+	result, err := Games.SubmitAnswer(request)
+	if err != nil {
+		return nil, rpcError(req.Header(), err)
+	}
+	return connect.NewResponse(&captriviav1.SubmitAnswerResponse{
+		AlreadyAnswered:   result.AlreadyAnswered,
+		Correct:           result.Correct,
+		Credit:            result.Credit,
+		CurrentScore:      int32(result.CurrentScore),
+		NextQuestionIndex: int32(result.NextQuestionIndex),
+	}), nil
+}
+
+func (service *GameRPCService) EndGame(ctx context.Context, req *connect.Request[captriviav1.EndGameRequest]) (*connect.Response[captriviav1.EndGameResponse], error) {
+	gameEnd, err := Games.EndGame(req.Msg.GameId, req.Msg.SessionId)
+	if err != nil {
+		return nil, rpcError(req.Header(), err)
+	}
+
+	response := &captriviav1.EndGameResponse{
+		FinalScore:  int32(gameEnd.FinalScore),
+		Multiplayer: gameEnd.Multiplayer,
+		Finished:    gameEnd.Finished,
+	}
+	for _, player := range gameEnd.Players {
+		response.Players = append(response.Players, &captriviav1.PlayerScore{Name: player.Name, SessionId: player.SessionID, Score: int32(player.Score)})
+	}
+	for _, entry := range gameEnd.Leaderboard {
+		response.Leaderboard = append(response.Leaderboard, &captriviav1.LeaderboardEntry{
+			Rank:        int32(entry.Rank),
+			PlayerId:    entry.PlayerID,
+			Name:        entry.Name,
+			GamesPlayed: int32(entry.GamesPlayed),
+			Correct:     int32(entry.Correct),
+			Answered:    int32(entry.Answered),
+			Accuracy:    entry.Accuracy,
+			BestScore:   int32(entry.BestScore),
+			TotalScore:  int32(entry.TotalScore),
+			Rating:      entry.Rating,
+		})
+	}
+	return connect.NewResponse(response), nil
+}
+
+// StreamEvents sends the messages of the game room until the client goes away or the session is removed
+func (service *GameRPCService) StreamEvents(ctx context.Context, req *connect.Request[captriviav1.StreamEventsRequest], stream *connect.ServerStream[captriviav1.Event]) error {
+	client, unsubscribe, err := Games.SubscribeGame(req.Msg.GameId, req.Msg.SessionId)
+	if err != nil {
+		return rpcError(req.Header(), err)
+	}
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-client.Send:
+			if !ok {
+				// The hub removed the client
+				return nil
+			}
+			event, err := rpcEvent(message)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Content of the room messages about a single player, the hub sends every value as a string
+type playerEventContent struct {
+	Name      string `json:"name"`
+	SessionID string `json:"sessionId"`
+	PowerUp   string `json:"powerUp"`
+	Banned    bool   `json:"banned,string"`
+}
+
+// rpcEvent converts a room message to an event, its JSON content is decoded into the payload of its type
+func rpcEvent(message models.Message) (*captriviav1.Event, error) {
+	event := &captriviav1.Event{Type: message.Type, Id: message.ID}
+	switch message.Type {
+	case "allPlayers", "scoreUpdate", "gameFinished":
+		var scores []PlayerScore
+		if err := json.Unmarshal([]byte(message.Content), &scores); err != nil {
+			return nil, err
+		}
+		players := &captriviav1.PlayerScores{}
+		for _, score := range scores {
+			players.Players = append(players.Players, &captriviav1.PlayerScore{Name: score.Name, SessionId: score.SessionID, Score: int32(score.Score)})
+		}
+		event.Payload = &captriviav1.Event_Players{Players: players}
+	case "playerJoined", "powerUpUsed", "playerRemoved":
+		var content playerEventContent
+		if err := json.Unmarshal([]byte(message.Content), &content); err != nil {
+			return nil, err
+		}
+		if message.Type == "playerRemoved" {
+			event.Payload = &captriviav1.Event_Removed{Removed: &captriviav1.PlayerRemoved{Name: content.Name, SessionId: content.SessionID, Banned: content.Banned}}
+		} else {
+			event.Payload = &captriviav1.Event_Player{Player: &captriviav1.PlayerEvent{Name: content.Name, SessionId: content.SessionID, PowerUp: content.PowerUp}}
+		}
+	case "startGameCountdown":
+		var content struct {
+			SecondsLeft int32 `json:"secondsLeft,string"`
+		}
+		if err := json.Unmarshal([]byte(message.Content), &content); err != nil {
+			return nil, err
+		}
+		event.Payload = &captriviav1.Event_Countdown{Countdown: &captriviav1.Countdown{SecondsLeft: content.SecondsLeft}}
+	case "startGame":
+		var content struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(message.Content), &content); err != nil {
+			return nil, err
+		}
+		event.Payload = &captriviav1.Event_Started{Started: &captriviav1.GameStarted{Message: content.Message}}
+	}
+	return event, nil
+}
+
+// withPlayerCookie gives a new player the cookie of the identity they were issued
+func withPlayerCookie[T any](response *connect.Response[T], cookie *http.Cookie) *connect.Response[T] {
+	if cookie != nil {
+		response.Header().Add("Set-Cookie", cookie.String())
+	}
+	return response
+}
+
+// rpcLocale picks the locale of a new session, the one asked for in the message wins over Accept-Language
+func rpcLocale(header http.Header, requested string) string {
+	if requested != "" {
+		return i18n.Normalize(requested)
+	}
+	return headerLocale(header.Get("Accept-Language"))
+}
+
+// Connect codes of the HTTP statuses of API errors
+var rpcCodes = map[int]connect.Code{
+	http.StatusBadRequest:   connect.CodeInvalidArgument,
+	http.StatusUnauthorized: connect.CodeUnauthenticated,
+	http.StatusForbidden:    connect.CodePermissionDenied,
+	http.StatusNotFound:     connect.CodeNotFound,
+	http.StatusConflict:     connect.CodeFailedPrecondition,
+}
+
+// rpcError is the RPC form of an error, the message is localized like REST error responses
+func rpcError(header http.Header, err error) error {
+	apiError := toAPIError(err)
+	locale := headerLocale(header.Get("Accept-Language"))
+	message := apiError.Message
+	if apiError.Key != "" {
+		message = i18n.Message(locale, apiError.Key)
+	}
+
+	code, known := rpcCodes[apiError.Status]
+	if !known {
+		code = connect.CodeInternal
+	}
+	rpcError := connect.NewError(code, errors.New(message))
+	rpcError.Meta().Set("Error-Code", string(apiError.Code))
+	if details := apiError.localizedDetails(locale); details != nil {
+		encoded, _ := json.Marshal(details)
+		rpcError.Meta().Set("Error-Details", string(encoded))
+	}
+	return rpcError
+}
+
+// answerJSON converts an answer to the JSON the question types grade, nil when no answer is set
+func answerJSON(answer *captriviav1.Answer) json.RawMessage {
+	var value interface{}
+	switch answer.GetValue().(type) {
+	case *captriviav1.Answer_Option:
+		value = answer.GetOption()
+	case *captriviav1.Answer_IsTrue:
+		value = answer.GetIsTrue()
+	case *captriviav1.Answer_Options:
+		value = append([]int32{}, answer.GetOptions().GetIndexes()...)
+	case *captriviav1.Answer_Number:
+		value = answer.GetNumber()
+	case *captriviav1.Answer_Order:
+		value = append([]int32{}, answer.GetOrder().GetIndexes()...)
+	default:
+		return nil
+	}
+
+	content, _ := json.Marshal(value)
+	return content
+}
+
+func optionIndexes(options []int) *captriviav1.OptionIndexes {
+	indexes := make([]int32, len(options))
+	for i, option := range options {
+		indexes[i] = int32(option)
+	}
+	return &captriviav1.OptionIndexes{Indexes: indexes}
+}
@@ -0,0 +1,298 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/models"
+	"github.com/ProlificLabs/captrivia/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Seconds the players of a match have to play its game when the organizer does not choose
+const defaultMatchSeconds = 10 * 60
+
+// tournamentGame is the game built for a match with the session of every player in it
+type tournamentGame struct {
+	match      *models.TournamentMatch
+	gameServer *models.GameServer
+	sessionIDs map[string]string
+}
+
+// createTournamentGames starts the games of a newly paired round. Every game is built before any is started,
+// if one cannot be built the pairing of the round is undone and no game is left behind.
+func createTournamentGames(tournament *models.Tournament, matches []*models.TournamentMatch) error {
+	games, err := buildTournamentGames(tournament, matches)
+	if err != nil {
+		tournament.UndoRound()
+		return err
+	}
+
+	startTournamentGames(tournament, games)
+	return nil
+}
+
+// buildTournamentGames builds a two player game for every match without saving it, the first player becomes the owner
+func buildTournamentGames(tournament *models.Tournament, matches []*models.TournamentMatch) ([]tournamentGame, error) {
+	games := make([]tournamentGame, 0, len(matches))
+	for _, match := range matches {
+		questions, err := Games.LoadQuestions(tournament.Questions, tournament.Category)
+		if err != nil {
+			return nil, err
+		}
+
+		settings := models.GameSettings{
+			Capacity:     len(match.PlayerIDs),
+			Category:     tournament.Category,
+			NamePolicy:   models.NamePolicySuffix,
+			Mode:         models.GameModeClassic,
+			TournamentID: tournament.ID,
+		}
+		gameServer := Games.buildGameServer(questions, models.NewSessionStore(settings.NamePolicy), true, settings)
+
+		sessionIDs := make(map[string]string, len(match.PlayerIDs))
+		for _, playerID := range match.PlayerIDs {
+			sessionID, err := gameServer.Sessions.CreateSession(tournament.PlayerName(playerID), playerID)
+			if err != nil {
+				return nil, err
+			}
+			if gameServer.Owner == "" {
+				gameServer.Owner = sessionID
+			}
+			sessionIDs[playerID] = sessionID
+		}
+		games = append(games, tournamentGame{match: match, gameServer: gameServer, sessionIDs: sessionIDs})
+	}
+	return games, nil
+}
+
+// startTournamentGames saves the games and records them on their matches.
+// A game still running at the deadline of its match is finished, so an absent player cannot stall the bracket.
+func startTournamentGames(tournament *models.Tournament, games []tournamentGame) {
+	timeout := time.Duration(tournament.MatchSeconds) * time.Second
+	for _, game := range games {
+		gameServer := game.gameServer
+		Games.games.Save(gameServer)
+		tournament.SetMatchGame(game.match.ID, gameServer.ID, game.sessionIDs, Games.clock.Now().Add(timeout))
+		Games.clock.AfterFunc(timeout, func() {
+			forfeitTournamentGame(gameServer)
+		})
+	}
+}
+
+// forfeitTournamentGame finishes a match game at its deadline, players who have not answered anything forfeit
+func forfeitTournamentGame(gameServer *models.GameServer) {
+	if Games.finishGameServer(gameServer) {
+		Games.events.GameFinished(gameServer)
+	}
+}
+
+// recordTournamentMatch advances the bracket with the result of a finished tournament game,
+// the result is kept even when the games of the next round cannot be created
+func recordTournamentMatch(gameServer *models.GameServer) error {
+	tournament, err := models.GetTournament(gameServer.Settings.TournamentID)
+	if err != nil {
+		return err
+	}
+
+	sessions := gameServer.Sessions.All()
+	results := make([]models.MatchResult, 0, len(sessions))
+	for _, session := range sessions {
+		// Players who never answered did not show up, they forfeit like players who left
+		if len(session.Answers) == 0 {
+			continue
+		}
+		results = append(results, models.MatchResult{PlayerID: session.PlayerID, Score: session.Score, Finished: session.Finished})
+	}
+
+	matches, err := tournament.RecordResult(gameServer.ID, results)
+	if err != nil {
+		return err
+	}
+
+	err = createTournamentGames(tournament, matches)
+	SendTournamentUpdateMessage(tournament)
+	return err
+}
+
+// The organizer token must match for every change to a tournament
+func getOrganizedTournament(c *gin.Context, organizerToken string) (*models.Tournament, bool) {
+	tournament, err := models.GetTournament(c.Param("tournamentID"))
+	if err != nil {
+		abortWithError(c, err)
+		return nil, false
+	}
+
+	if tournament.OrganizerToken != organizerToken {
+		abortWithError(c, errNotOrganizer)
+		return nil, false
+	}
+	return tournament, true
+}
+
+// Create a tournament, the organizer token in the response is needed to register players and start it
+func CreateTournamentHandler(c *gin.Context) {
+	var request struct {
+		Name      string `json:"name" binding:"required"`
+		Format    string `json:"format" binding:"tournamentformat"`
+		Questions int    `json:"questions" binding:"min=0"`
+		Category  string `json:"category"`
+		// Only used by Swiss tournaments, enough rounds to find a single winner when not set
+		Rounds int `json:"rounds" binding:"min=0"`
+		// Seconds the players of a match have to play its game, 10 minutes when not set
+		MatchSeconds int `json:"matchSeconds" binding:"min=0"`
+	}
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	format := models.TournamentFormat(request.Format)
+	if request.Format == "" {
+		format = models.TournamentSingleElimination
+	}
+
+	if request.Questions == 0 {
+		request.Questions = defaultMatchQuestions
+	}
+	if request.MatchSeconds == 0 {
+		request.MatchSeconds = defaultMatchSeconds
+	}
+
+	// Make sure the match games can be created before anyone registers
+	if err := Games.checkQuestionCount(request.Questions); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	if _, err := Games.LoadQuestions(request.Questions, request.Category); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	tournament := models.NewTournament(request.Name, format, request.Questions, request.Category, request.Rounds, request.MatchSeconds)
+	c.JSON(http.StatusOK, gin.H{"tournamentId": tournament.ID, "organizerToken": tournament.OrganizerToken})
+}
+
+// Register a player in a tournament before it starts. The player ID is the one GET /players/me gives the
+// player, it is what they prove with their cookie to get the session of their match.
+func RegisterTournamentPlayerHandler(c *gin.Context) {
+	var request struct {
+		OrganizerToken string `json:"organizerToken" binding:"required"`
+		Name           string `json:"name" binding:"playername"`
+		PlayerID       string `json:"playerId" binding:"required"`
+	}
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	tournament, ok := getOrganizedTournament(c, request.OrganizerToken)
+	if !ok {
+		return
+	}
+
+	if err := tournament.AddPlayer(request.PlayerID, request.Name); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	SendTournamentUpdateMessage(tournament)
+	c.JSON(http.StatusOK, gin.H{"tournamentId": tournament.ID, "playerId": request.PlayerID, "name": request.Name})
+}
+
+// Close registration and create the games of the first round
+func StartTournamentHandler(c *gin.Context) {
+	var request struct {
+		OrganizerToken string `json:"organizerToken" binding:"required"`
+	}
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	tournament, ok := getOrganizedTournament(c, request.OrganizerToken)
+	if !ok {
+		return
+	}
+
+	matches, err := tournament.Start(Games.stores.Ratings)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := createTournamentGames(tournament, matches); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	SendTournamentUpdateMessage(tournament)
+	GetTournamentHandler(c)
+}
+
+// Get the match the caller has to play now with their session in its game
+func GetTournamentMatchHandler(c *gin.Context) {
+	tournament, err := models.GetTournament(c.Param("tournamentID"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	playerID := playerIdentity(c)
+	match, err := tournament.CurrentMatch(playerID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matchId":   match.ID,
+		"round":     match.Round,
+		"playerIds": match.PlayerIDs,
+		"gameId":    match.GameID,
+		"sessionId": match.SessionIDs[playerID],
+		"deadline":  match.Deadline,
+	})
+}
+
+// Get the bracket of a tournament, every match lists its game but not the sessions of its players
+func GetTournamentHandler(c *gin.Context) {
+	tournament, err := models.GetTournament(c.Param("tournamentID"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	content, err := tournament.JSON()
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", content)
+}
+
+// Subscribe to bracket updates of a tournament
+func TournamentWebSocketHandler(c *gin.Context) {
+	tournament, err := models.GetTournament(c.Param("tournamentID"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	socket, err := utils.UpgradeToWebSocket(c.Writer, c.Request)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	hub := models.GetOrCreateHub()
+	client := models.NewClient(tournament.ID, socket, hub)
+
+	hub.Register <- client
+	go client.Write()
+	go client.Read()
+
+	content, err := tournament.JSON()
+	if err != nil {
+		return
+	}
+	client.Deliver(models.Message{Type: "tournamentUpdate", ID: tournament.ID, Content: string(content)})
+}
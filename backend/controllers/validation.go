@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/ProlificLabs/captrivia/i18n"
+	"github.com/ProlificLabs/captrivia/models"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var errValidationFailed = newAPIError(http.StatusBadRequest, CodeValidationFailed, i18n.ValidationFailed)
+
+// Custom validators used in the binding tags of request structs
+var customValidators = map[string]validator.Func{
+	// A name that passes models.ValidatePlayerName
+	"playername": func(fl validator.FieldLevel) bool {
+		return models.ValidatePlayerName(fl.Field().String()) == nil
+	},
+	"namepolicy": func(fl validator.FieldLevel) bool {
+		return models.NamePolicy(fl.Field().String()).IsValid()
+	},
+	"gamemode": func(fl validator.FieldLevel) bool {
+		return models.GameMode(fl.Field().String()).IsValid()
+	},
+	"powerup": func(fl validator.FieldLevel) bool {
+		return models.PowerUp(fl.Field().String()).IsValid()
+	},
+	"tournamentformat": func(fl validator.FieldLevel) bool {
+		format := fl.Field().String()
+		return format == "" || models.TournamentFormat(format).IsValid()
+	},
+}
+
+// RegisterValidators adds the custom validators to the validator gin binds requests with
+// and makes validation errors use the JSON names of the fields
+func RegisterValidators() error {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected binding validator")
+	}
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	for tag, validatorFunc := range customValidators {
+		if err := validate.RegisterValidation(tag, validatorFunc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindJSON binds and validates the request body, failures are reported with the reason for every field
+func bindJSON(c *gin.Context, request interface{}) bool {
+	if err := c.ShouldBindJSON(request); err != nil {
+		abortWithError(c, requestError(err))
+		return false
+	}
+	return true
+}
+
+// validateRequest checks the binding tags of a request that was not bound by gin, like one built from an RPC message
+func validateRequest(request interface{}) error {
+	if err := binding.Validator.ValidateStruct(request); err != nil {
+		return requestError(err)
+	}
+	return nil
+}
+
+// requestError is the error of a request that could not be bound or validated
+func requestError(err error) *APIError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return errInvalidRequestBody
+	}
+
+	fields := make(fieldErrors, len(validationErrors))
+	for _, validationError := range validationErrors {
+		fields[validationError.Field()] = newFieldError(validationError)
+	}
+	return errValidationFailed.WithFields(fields)
+}
+
+// fieldError explains why a field failed validation, it is rendered in the locale of the request
+type fieldError struct {
+	key  i18n.Key
+	args []interface{}
+}
+
+// fieldErrors holds the reason of every field that failed validation by the JSON name of the field
+type fieldErrors map[string]fieldError
+
+// newFieldError returns the reason of a binding tag that failed
+func newFieldError(validationError validator.FieldError) fieldError {
+	switch validationError.Tag() {
+	case "required", "required_unless":
+		return fieldError{key: i18n.FieldRequired}
+	case "playername":
+		return fieldError{key: toAPIError(models.ValidatePlayerName(validationError.Value().(string))).Key}
+	case "min":
+		return fieldError{key: i18n.FieldTooSmall, args: []interface{}{validationError.Param()}}
+	case "max":
+		return fieldError{key: i18n.FieldTooLarge, args: []interface{}{validationError.Param()}}
+	default:
+		return fieldError{key: i18n.FieldInvalid}
+	}
+}
+
+// localize returns the reasons in the locale
+func (fields fieldErrors) localize(locale string) map[string]string {
+	messages := make(map[string]string, len(fields))
+	for field, reason := range fields {
+		messages[field] = fmt.Sprintf(i18n.Message(locale, reason.key), reason.args...)
+	}
+	return messages
+}
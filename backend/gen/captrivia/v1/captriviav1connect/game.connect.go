@@ -0,0 +1,264 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: captrivia/v1/game.proto
+
+// Typed API of Captrivia games, served next to the REST API by the same service layer.
+// Regenerate the Go code in gen/ with `buf generate` from the backend directory.
+package captriviav1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/ProlificLabs/captrivia/gen/captrivia/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// GameServiceName is the fully-qualified name of the GameService service.
+	GameServiceName = "captrivia.v1.GameService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// GameServiceStartGameProcedure is the fully-qualified name of the GameService's StartGame RPC.
+	GameServiceStartGameProcedure = "/captrivia.v1.GameService/StartGame"
+	// GameServiceJoinGameProcedure is the fully-qualified name of the GameService's JoinGame RPC.
+	GameServiceJoinGameProcedure = "/captrivia.v1.GameService/JoinGame"
+	// GameServiceGetGameProcedure is the fully-qualified name of the GameService's GetGame RPC.
+	GameServiceGetGameProcedure = "/captrivia.v1.GameService/GetGame"
+	// GameServiceSubmitAnswerProcedure is the fully-qualified name of the GameService's SubmitAnswer
+	// RPC.
+	GameServiceSubmitAnswerProcedure = "/captrivia.v1.GameService/SubmitAnswer"
+	// GameServiceEndGameProcedure is the fully-qualified name of the GameService's EndGame RPC.
+	GameServiceEndGameProcedure = "/captrivia.v1.GameService/EndGame"
+	// GameServiceStreamEventsProcedure is the fully-qualified name of the GameService's StreamEvents
+	// RPC.
+	GameServiceStreamEventsProcedure = "/captrivia.v1.GameService/StreamEvents"
+)
+
+// GameServiceClient is a client for the captrivia.v1.GameService service.
+type GameServiceClient interface {
+	// Start a new game, the player that starts it owns it
+	StartGame(context.Context, *connect.Request[v1.StartGameRequest]) (*connect.Response[v1.StartGameResponse], error)
+	// Join a multiplayer game
+	JoinGame(context.Context, *connect.Request[v1.JoinGameRequest]) (*connect.Response[v1.JoinGameResponse], error)
+	// Get the questions and the progress of a session, without the answers
+	GetGame(context.Context, *connect.Request[v1.GetGameRequest]) (*connect.Response[v1.GetGameResponse], error)
+	// Answer a question, the first correct answer in a multiplayer game gets the points
+	SubmitAnswer(context.Context, *connect.Request[v1.SubmitAnswerRequest]) (*connect.Response[v1.SubmitAnswerResponse], error)
+	// Get the final score of a session, the game finishes once every player has finished
+	EndGame(context.Context, *connect.Request[v1.EndGameRequest]) (*connect.Response[v1.EndGameResponse], error)
+	// Receive the events of the game room, the same events the websocket sends
+	StreamEvents(context.Context, *connect.Request[v1.StreamEventsRequest]) (*connect.ServerStreamForClient[v1.Event], error)
+}
+
+// NewGameServiceClient constructs a client for the captrivia.v1.GameService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewGameServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) GameServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	gameServiceMethods := v1.File_captrivia_v1_game_proto.Services().ByName("GameService").Methods()
+	return &gameServiceClient{
+		startGame: connect.NewClient[v1.StartGameRequest, v1.StartGameResponse](
+			httpClient,
+			baseURL+GameServiceStartGameProcedure,
+			connect.WithSchema(gameServiceMethods.ByName("StartGame")),
+			connect.WithClientOptions(opts...),
+		),
+		joinGame: connect.NewClient[v1.JoinGameRequest, v1.JoinGameResponse](
+			httpClient,
+			baseURL+GameServiceJoinGameProcedure,
+			connect.WithSchema(gameServiceMethods.ByName("JoinGame")),
+			connect.WithClientOptions(opts...),
+		),
+		getGame: connect.NewClient[v1.GetGameRequest, v1.GetGameResponse](
+			httpClient,
+			baseURL+GameServiceGetGameProcedure,
+			connect.WithSchema(gameServiceMethods.ByName("GetGame")),
+			connect.WithClientOptions(opts...),
+		),
+		submitAnswer: connect.NewClient[v1.SubmitAnswerRequest, v1.SubmitAnswerResponse](
+			httpClient,
+			baseURL+GameServiceSubmitAnswerProcedure,
+			connect.WithSchema(gameServiceMethods.ByName("SubmitAnswer")),
+			connect.WithClientOptions(opts...),
+		),
+		endGame: connect.NewClient[v1.EndGameRequest, v1.EndGameResponse](
+			httpClient,
+			baseURL+GameServiceEndGameProcedure,
+			connect.WithSchema(gameServiceMethods.ByName("EndGame")),
+			connect.WithClientOptions(opts...),
+		),
+		streamEvents: connect.NewClient[v1.StreamEventsRequest, v1.Event](
+			httpClient,
+			baseURL+GameServiceStreamEventsProcedure,
+			connect.WithSchema(gameServiceMethods.ByName("StreamEvents")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// gameServiceClient implements GameServiceClient.
+type gameServiceClient struct {
+	startGame    *connect.Client[v1.StartGameRequest, v1.StartGameResponse]
+	joinGame     *connect.Client[v1.JoinGameRequest, v1.JoinGameResponse]
+	getGame      *connect.Client[v1.GetGameRequest, v1.GetGameResponse]
+	submitAnswer *connect.Client[v1.SubmitAnswerRequest, v1.SubmitAnswerResponse]
+	endGame      *connect.Client[v1.EndGameRequest, v1.EndGameResponse]
+	streamEvents *connect.Client[v1.StreamEventsRequest, v1.Event]
+}
+
+// StartGame calls captrivia.v1.GameService.StartGame.
+func (c *gameServiceClient) StartGame(ctx context.Context, req *connect.Request[v1.StartGameRequest]) (*connect.Response[v1.StartGameResponse], error) {
+	return c.startGame.CallUnary(ctx, req)
+}
+
+// JoinGame calls captrivia.v1.GameService.JoinGame.
+func (c *gameServiceClient) JoinGame(ctx context.Context, req *connect.Request[v1.JoinGameRequest]) (*connect.Response[v1.JoinGameResponse], error) {
+	return c.joinGame.CallUnary(ctx, req)
+}
+
+// GetGame calls captrivia.v1.GameService.GetGame.
+func (c *gameServiceClient) GetGame(ctx context.Context, req *connect.Request[v1.GetGameRequest]) (*connect.Response[v1.GetGameResponse], error) {
+	return c.getGame.CallUnary(ctx, req)
+}
+
+// SubmitAnswer calls captrivia.v1.GameService.SubmitAnswer.
+func (c *gameServiceClient) SubmitAnswer(ctx context.Context, req *connect.Request[v1.SubmitAnswerRequest]) (*connect.Response[v1.SubmitAnswerResponse], error) {
+	return c.submitAnswer.CallUnary(ctx, req)
+}
+
+// EndGame calls captrivia.v1.GameService.EndGame.
+func (c *gameServiceClient) EndGame(ctx context.Context, req *connect.Request[v1.EndGameRequest]) (*connect.Response[v1.EndGameResponse], error) {
+	return c.endGame.CallUnary(ctx, req)
+}
+
+// StreamEvents calls captrivia.v1.GameService.StreamEvents.
+func (c *gameServiceClient) StreamEvents(ctx context.Context, req *connect.Request[v1.StreamEventsRequest]) (*connect.ServerStreamForClient[v1.Event], error) {
+	return c.streamEvents.CallServerStream(ctx, req)
+}
+
+// GameServiceHandler is an implementation of the captrivia.v1.GameService service.
+type GameServiceHandler interface {
+	// Start a new game, the player that starts it owns it
+	StartGame(context.Context, *connect.Request[v1.StartGameRequest]) (*connect.Response[v1.StartGameResponse], error)
+	// Join a multiplayer game
+	JoinGame(context.Context, *connect.Request[v1.JoinGameRequest]) (*connect.Response[v1.JoinGameResponse], error)
+	// Get the questions and the progress of a session, without the answers
+	GetGame(context.Context, *connect.Request[v1.GetGameRequest]) (*connect.Response[v1.GetGameResponse], error)
+	// Answer a question, the first correct answer in a multiplayer game gets the points
+	SubmitAnswer(context.Context, *connect.Request[v1.SubmitAnswerRequest]) (*connect.Response[v1.SubmitAnswerResponse], error)
+	// Get the final score of a session, the game finishes once every player has finished
+	EndGame(context.Context, *connect.Request[v1.EndGameRequest]) (*connect.Response[v1.EndGameResponse], error)
+	// Receive the events of the game room, the same events the websocket sends
+	StreamEvents(context.Context, *connect.Request[v1.StreamEventsRequest], *connect.ServerStream[v1.Event]) error
+}
+
+// NewGameServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewGameServiceHandler(svc GameServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	gameServiceMethods := v1.File_captrivia_v1_game_proto.Services().ByName("GameService").Methods()
+	gameServiceStartGameHandler := connect.NewUnaryHandler(
+		GameServiceStartGameProcedure,
+		svc.StartGame,
+		connect.WithSchema(gameServiceMethods.ByName("StartGame")),
+		connect.WithHandlerOptions(opts...),
+	)
+	gameServiceJoinGameHandler := connect.NewUnaryHandler(
+		GameServiceJoinGameProcedure,
+		svc.JoinGame,
+		connect.WithSchema(gameServiceMethods.ByName("JoinGame")),
+		connect.WithHandlerOptions(opts...),
+	)
+	gameServiceGetGameHandler := connect.NewUnaryHandler(
+		GameServiceGetGameProcedure,
+		svc.GetGame,
+		connect.WithSchema(gameServiceMethods.ByName("GetGame")),
+		connect.WithHandlerOptions(opts...),
+	)
+	gameServiceSubmitAnswerHandler := connect.NewUnaryHandler(
+		GameServiceSubmitAnswerProcedure,
+		svc.SubmitAnswer,
+		connect.WithSchema(gameServiceMethods.ByName("SubmitAnswer")),
+		connect.WithHandlerOptions(opts...),
+	)
+	gameServiceEndGameHandler := connect.NewUnaryHandler(
+		GameServiceEndGameProcedure,
+		svc.EndGame,
+		connect.WithSchema(gameServiceMethods.ByName("EndGame")),
+		connect.WithHandlerOptions(opts...),
+	)
+	gameServiceStreamEventsHandler := connect.NewServerStreamHandler(
+		GameServiceStreamEventsProcedure,
+		svc.StreamEvents,
+		connect.WithSchema(gameServiceMethods.ByName("StreamEvents")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/captrivia.v1.GameService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case GameServiceStartGameProcedure:
+			gameServiceStartGameHandler.ServeHTTP(w, r)
+		case GameServiceJoinGameProcedure:
+			gameServiceJoinGameHandler.ServeHTTP(w, r)
+		case GameServiceGetGameProcedure:
+			gameServiceGetGameHandler.ServeHTTP(w, r)
+		case GameServiceSubmitAnswerProcedure:
+			gameServiceSubmitAnswerHandler.ServeHTTP(w, r)
+		case GameServiceEndGameProcedure:
+			gameServiceEndGameHandler.ServeHTTP(w, r)
+		case GameServiceStreamEventsProcedure:
+			gameServiceStreamEventsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedGameServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedGameServiceHandler struct{}
+
+func (UnimplementedGameServiceHandler) StartGame(context.Context, *connect.Request[v1.StartGameRequest]) (*connect.Response[v1.StartGameResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("captrivia.v1.GameService.StartGame is not implemented"))
+}
+
+func (UnimplementedGameServiceHandler) JoinGame(context.Context, *connect.Request[v1.JoinGameRequest]) (*connect.Response[v1.JoinGameResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("captrivia.v1.GameService.JoinGame is not implemented"))
+}
+
+func (UnimplementedGameServiceHandler) GetGame(context.Context, *connect.Request[v1.GetGameRequest]) (*connect.Response[v1.GetGameResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("captrivia.v1.GameService.GetGame is not implemented"))
+}
+
+func (UnimplementedGameServiceHandler) SubmitAnswer(context.Context, *connect.Request[v1.SubmitAnswerRequest]) (*connect.Response[v1.SubmitAnswerResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("captrivia.v1.GameService.SubmitAnswer is not implemented"))
+}
+
+func (UnimplementedGameServiceHandler) EndGame(context.Context, *connect.Request[v1.EndGameRequest]) (*connect.Response[v1.EndGameResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("captrivia.v1.GameService.EndGame is not implemented"))
+}
+
+func (UnimplementedGameServiceHandler) StreamEvents(context.Context, *connect.Request[v1.StreamEventsRequest], *connect.ServerStream[v1.Event]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("captrivia.v1.GameService.StreamEvents is not implemented"))
+}
@@ -0,0 +1,1980 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: captrivia/v1/game.proto
+
+// Typed API of Captrivia games, served next to the REST API by the same service layer.
+// Regenerate the Go code in gen/ with `buf generate` from the backend directory.
+
+package captriviav1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StartGameRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Multiplayer bool                   `protobuf:"varint,3,opt,name=multiplayer,proto3" json:"multiplayer,omitempty"`
+	// Required unless the mode is daily
+	Questions int32 `protobuf:"varint,4,opt,name=questions,proto3" json:"questions,omitempty"`
+	Public    bool  `protobuf:"varint,5,opt,name=public,proto3" json:"public,omitempty"`
+	// Maximum number of players, 8 when not set
+	Capacity int32  `protobuf:"varint,6,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Category string `protobuf:"bytes,7,opt,name=category,proto3" json:"category,omitempty"`
+	// suffix or reject
+	NamePolicy string `protobuf:"bytes,8,opt,name=name_policy,json=namePolicy,proto3" json:"name_policy,omitempty"`
+	// classic, practice or daily
+	Mode string `protobuf:"bytes,9,opt,name=mode,proto3" json:"mode,omitempty"`
+	// Uses of each power-up per session, every power-up can be used once when empty
+	PowerUps map[string]int32 `protobuf:"bytes,10,rep,name=power_ups,json=powerUps,proto3" json:"power_ups,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Locale of the questions, Accept-Language is used when not set
+	Locale        string `protobuf:"bytes,11,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartGameRequest) Reset() {
+	*x = StartGameRequest{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartGameRequest) ProtoMessage() {}
+
+func (x *StartGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartGameRequest.ProtoReflect.Descriptor instead.
+func (*StartGameRequest) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StartGameRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StartGameRequest) GetMultiplayer() bool {
+	if x != nil {
+		return x.Multiplayer
+	}
+	return false
+}
+
+func (x *StartGameRequest) GetQuestions() int32 {
+	if x != nil {
+		return x.Questions
+	}
+	return 0
+}
+
+func (x *StartGameRequest) GetPublic() bool {
+	if x != nil {
+		return x.Public
+	}
+	return false
+}
+
+func (x *StartGameRequest) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *StartGameRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *StartGameRequest) GetNamePolicy() string {
+	if x != nil {
+		return x.NamePolicy
+	}
+	return ""
+}
+
+func (x *StartGameRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *StartGameRequest) GetPowerUps() map[string]int32 {
+	if x != nil {
+		return x.PowerUps
+	}
+	return nil
+}
+
+func (x *StartGameRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type StartGameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,3,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartGameResponse) Reset() {
+	*x = StartGameResponse{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartGameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartGameResponse) ProtoMessage() {}
+
+func (x *StartGameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartGameResponse.ProtoReflect.Descriptor instead.
+func (*StartGameResponse) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StartGameResponse) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *StartGameResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *StartGameResponse) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+type JoinGameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Locale        string                 `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinGameRequest) Reset() {
+	*x = JoinGameRequest{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinGameRequest) ProtoMessage() {}
+
+func (x *JoinGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinGameRequest.ProtoReflect.Descriptor instead.
+func (*JoinGameRequest) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *JoinGameRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *JoinGameRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JoinGameRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type JoinGameResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	GameId    string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	SessionId string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	PlayerId  string                 `protobuf:"bytes,3,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	// The name given to the player, it can have a suffix when the name is taken
+	Name          string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinGameResponse) Reset() {
+	*x = JoinGameResponse{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinGameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinGameResponse) ProtoMessage() {}
+
+func (x *JoinGameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinGameResponse.ProtoReflect.Descriptor instead.
+func (*JoinGameResponse) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *JoinGameResponse) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *JoinGameResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *JoinGameResponse) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *JoinGameResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetGameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGameRequest) Reset() {
+	*x = GetGameRequest{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGameRequest) ProtoMessage() {}
+
+func (x *GetGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGameRequest.ProtoReflect.Descriptor instead.
+func (*GetGameRequest) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetGameRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *GetGameRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type Question struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Category     string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	QuestionText string                 `protobuf:"bytes,3,opt,name=question_text,json=questionText,proto3" json:"question_text,omitempty"`
+	Options      []string               `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty"`
+	// singleChoice, trueFalse, multiSelect, numeric or ordering
+	Type string `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	// How far off the answer of a numeric question can be
+	Tolerance     float64 `protobuf:"fixed64,6,opt,name=tolerance,proto3" json:"tolerance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Question) Reset() {
+	*x = Question{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Question) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Question) ProtoMessage() {}
+
+func (x *Question) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Question.ProtoReflect.Descriptor instead.
+func (*Question) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Question) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Question) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Question) GetQuestionText() string {
+	if x != nil {
+		return x.QuestionText
+	}
+	return ""
+}
+
+func (x *Question) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *Question) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Question) GetTolerance() float64 {
+	if x != nil {
+		return x.Tolerance
+	}
+	return 0
+}
+
+type OptionIndexes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Indexes       []int32                `protobuf:"varint,1,rep,packed,name=indexes,proto3" json:"indexes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OptionIndexes) Reset() {
+	*x = OptionIndexes{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OptionIndexes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OptionIndexes) ProtoMessage() {}
+
+func (x *OptionIndexes) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OptionIndexes.ProtoReflect.Descriptor instead.
+func (*OptionIndexes) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *OptionIndexes) GetIndexes() []int32 {
+	if x != nil {
+		return x.Indexes
+	}
+	return nil
+}
+
+type GetGameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Started       bool                   `protobuf:"varint,2,opt,name=started,proto3" json:"started,omitempty"`
+	Finished      bool                   `protobuf:"varint,3,opt,name=finished,proto3" json:"finished,omitempty"`
+	Multiplayer   bool                   `protobuf:"varint,4,opt,name=multiplayer,proto3" json:"multiplayer,omitempty"`
+	Mode          string                 `protobuf:"bytes,5,opt,name=mode,proto3" json:"mode,omitempty"`
+	Questions     []*Question            `protobuf:"bytes,6,rep,name=questions,proto3" json:"questions,omitempty"`
+	QuestionIndex int32                  `protobuf:"varint,7,opt,name=question_index,json=questionIndex,proto3" json:"question_index,omitempty"`
+	CurrentScore  int32                  `protobuf:"varint,8,opt,name=current_score,json=currentScore,proto3" json:"current_score,omitempty"`
+	// Uses left of each power-up
+	PowerUps     map[string]int32 `protobuf:"bytes,9,rep,name=power_ups,json=powerUps,proto3" json:"power_ups,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	DoublePoints bool             `protobuf:"varint,10,opt,name=double_points,json=doublePoints,proto3" json:"double_points,omitempty"`
+	// Options removed by fifty-fifty, keyed by question ID
+	RemovedOptions map[string]*OptionIndexes `protobuf:"bytes,11,rep,name=removed_options,json=removedOptions,proto3" json:"removed_options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Locale         string                    `protobuf:"bytes,12,opt,name=locale,proto3" json:"locale,omitempty"`
+	Owner          bool                      `protobuf:"varint,13,opt,name=owner,proto3" json:"owner,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetGameResponse) Reset() {
+	*x = GetGameResponse{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGameResponse) ProtoMessage() {}
+
+func (x *GetGameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGameResponse.ProtoReflect.Descriptor instead.
+func (*GetGameResponse) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetGameResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetGameResponse) GetStarted() bool {
+	if x != nil {
+		return x.Started
+	}
+	return false
+}
+
+func (x *GetGameResponse) GetFinished() bool {
+	if x != nil {
+		return x.Finished
+	}
+	return false
+}
+
+func (x *GetGameResponse) GetMultiplayer() bool {
+	if x != nil {
+		return x.Multiplayer
+	}
+	return false
+}
+
+func (x *GetGameResponse) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *GetGameResponse) GetQuestions() []*Question {
+	if x != nil {
+		return x.Questions
+	}
+	return nil
+}
+
+func (x *GetGameResponse) GetQuestionIndex() int32 {
+	if x != nil {
+		return x.QuestionIndex
+	}
+	return 0
+}
+
+func (x *GetGameResponse) GetCurrentScore() int32 {
+	if x != nil {
+		return x.CurrentScore
+	}
+	return 0
+}
+
+func (x *GetGameResponse) GetPowerUps() map[string]int32 {
+	if x != nil {
+		return x.PowerUps
+	}
+	return nil
+}
+
+func (x *GetGameResponse) GetDoublePoints() bool {
+	if x != nil {
+		return x.DoublePoints
+	}
+	return false
+}
+
+func (x *GetGameResponse) GetRemovedOptions() map[string]*OptionIndexes {
+	if x != nil {
+		return x.RemovedOptions
+	}
+	return nil
+}
+
+func (x *GetGameResponse) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *GetGameResponse) GetOwner() bool {
+	if x != nil {
+		return x.Owner
+	}
+	return false
+}
+
+// Answer is shaped after the type of the question
+type Answer struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*Answer_Option
+	//	*Answer_IsTrue
+	//	*Answer_Options
+	//	*Answer_Number
+	//	*Answer_Order
+	Value         isAnswer_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Answer) Reset() {
+	*x = Answer{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Answer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Answer) ProtoMessage() {}
+
+func (x *Answer) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Answer.ProtoReflect.Descriptor instead.
+func (*Answer) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Answer) GetValue() isAnswer_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Answer) GetOption() int32 {
+	if x != nil {
+		if x, ok := x.Value.(*Answer_Option); ok {
+			return x.Option
+		}
+	}
+	return 0
+}
+
+func (x *Answer) GetIsTrue() bool {
+	if x != nil {
+		if x, ok := x.Value.(*Answer_IsTrue); ok {
+			return x.IsTrue
+		}
+	}
+	return false
+}
+
+func (x *Answer) GetOptions() *OptionIndexes {
+	if x != nil {
+		if x, ok := x.Value.(*Answer_Options); ok {
+			return x.Options
+		}
+	}
+	return nil
+}
+
+func (x *Answer) GetNumber() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*Answer_Number); ok {
+			return x.Number
+		}
+	}
+	return 0
+}
+
+func (x *Answer) GetOrder() *OptionIndexes {
+	if x != nil {
+		if x, ok := x.Value.(*Answer_Order); ok {
+			return x.Order
+		}
+	}
+	return nil
+}
+
+type isAnswer_Value interface {
+	isAnswer_Value()
+}
+
+type Answer_Option struct {
+	// Index of the picked option of a single choice question
+	Option int32 `protobuf:"varint,1,opt,name=option,proto3,oneof"`
+}
+
+type Answer_IsTrue struct {
+	// Answer to a true/false question
+	IsTrue bool `protobuf:"varint,2,opt,name=is_true,json=isTrue,proto3,oneof"`
+}
+
+type Answer_Options struct {
+	// Every picked option of a multi-select question
+	Options *OptionIndexes `protobuf:"bytes,3,opt,name=options,proto3,oneof"`
+}
+
+type Answer_Number struct {
+	// Answer to a numeric question
+	Number float64 `protobuf:"fixed64,4,opt,name=number,proto3,oneof"`
+}
+
+type Answer_Order struct {
+	// Every option index of an ordering question, in order
+	Order *OptionIndexes `protobuf:"bytes,5,opt,name=order,proto3,oneof"`
+}
+
+func (*Answer_Option) isAnswer_Value() {}
+
+func (*Answer_IsTrue) isAnswer_Value() {}
+
+func (*Answer_Options) isAnswer_Value() {}
+
+func (*Answer_Number) isAnswer_Value() {}
+
+func (*Answer_Order) isAnswer_Value() {}
+
+type SubmitAnswerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	QuestionId    string                 `protobuf:"bytes,3,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Answer        *Answer                `protobuf:"bytes,4,opt,name=answer,proto3" json:"answer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitAnswerRequest) Reset() {
+	*x = SubmitAnswerRequest{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitAnswerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitAnswerRequest) ProtoMessage() {}
+
+func (x *SubmitAnswerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitAnswerRequest.ProtoReflect.Descriptor instead.
+func (*SubmitAnswerRequest) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SubmitAnswerRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *SubmitAnswerRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SubmitAnswerRequest) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *SubmitAnswerRequest) GetAnswer() *Answer {
+	if x != nil {
+		return x.Answer
+	}
+	return nil
+}
+
+type SubmitAnswerResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Another player answered correctly first, no points are given
+	AlreadyAnswered bool `protobuf:"varint,1,opt,name=already_answered,json=alreadyAnswered,proto3" json:"already_answered,omitempty"`
+	Correct         bool `protobuf:"varint,2,opt,name=correct,proto3" json:"correct,omitempty"`
+	// Share of the points earned
+	Credit            float64 `protobuf:"fixed64,3,opt,name=credit,proto3" json:"credit,omitempty"`
+	CurrentScore      int32   `protobuf:"varint,4,opt,name=current_score,json=currentScore,proto3" json:"current_score,omitempty"`
+	NextQuestionIndex int32   `protobuf:"varint,5,opt,name=next_question_index,json=nextQuestionIndex,proto3" json:"next_question_index,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SubmitAnswerResponse) Reset() {
+	*x = SubmitAnswerResponse{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitAnswerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitAnswerResponse) ProtoMessage() {}
+
+func (x *SubmitAnswerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitAnswerResponse.ProtoReflect.Descriptor instead.
+func (*SubmitAnswerResponse) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SubmitAnswerResponse) GetAlreadyAnswered() bool {
+	if x != nil {
+		return x.AlreadyAnswered
+	}
+	return false
+}
+
+func (x *SubmitAnswerResponse) GetCorrect() bool {
+	if x != nil {
+		return x.Correct
+	}
+	return false
+}
+
+func (x *SubmitAnswerResponse) GetCredit() float64 {
+	if x != nil {
+		return x.Credit
+	}
+	return 0
+}
+
+func (x *SubmitAnswerResponse) GetCurrentScore() int32 {
+	if x != nil {
+		return x.CurrentScore
+	}
+	return 0
+}
+
+func (x *SubmitAnswerResponse) GetNextQuestionIndex() int32 {
+	if x != nil {
+		return x.NextQuestionIndex
+	}
+	return 0
+}
+
+type EndGameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EndGameRequest) Reset() {
+	*x = EndGameRequest{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndGameRequest) ProtoMessage() {}
+
+func (x *EndGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndGameRequest.ProtoReflect.Descriptor instead.
+func (*EndGameRequest) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EndGameRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *EndGameRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type PlayerScore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Score         int32                  `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerScore) Reset() {
+	*x = PlayerScore{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerScore) ProtoMessage() {}
+
+func (x *PlayerScore) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerScore.ProtoReflect.Descriptor instead.
+func (*PlayerScore) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PlayerScore) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PlayerScore) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *PlayerScore) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type LeaderboardEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rank          int32                  `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	GamesPlayed   int32                  `protobuf:"varint,4,opt,name=games_played,json=gamesPlayed,proto3" json:"games_played,omitempty"`
+	Correct       int32                  `protobuf:"varint,5,opt,name=correct,proto3" json:"correct,omitempty"`
+	Answered      int32                  `protobuf:"varint,6,opt,name=answered,proto3" json:"answered,omitempty"`
+	Accuracy      float64                `protobuf:"fixed64,7,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	BestScore     int32                  `protobuf:"varint,8,opt,name=best_score,json=bestScore,proto3" json:"best_score,omitempty"`
+	TotalScore    int32                  `protobuf:"varint,9,opt,name=total_score,json=totalScore,proto3" json:"total_score,omitempty"`
+	Rating        float64                `protobuf:"fixed64,10,opt,name=rating,proto3" json:"rating,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaderboardEntry) Reset() {
+	*x = LeaderboardEntry{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaderboardEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaderboardEntry) ProtoMessage() {}
+
+func (x *LeaderboardEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaderboardEntry.ProtoReflect.Descriptor instead.
+func (*LeaderboardEntry) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LeaderboardEntry) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *LeaderboardEntry) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LeaderboardEntry) GetGamesPlayed() int32 {
+	if x != nil {
+		return x.GamesPlayed
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetCorrect() int32 {
+	if x != nil {
+		return x.Correct
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetAnswered() int32 {
+	if x != nil {
+		return x.Answered
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetAccuracy() float64 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetBestScore() int32 {
+	if x != nil {
+		return x.BestScore
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetTotalScore() int32 {
+	if x != nil {
+		return x.TotalScore
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+type EndGameResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	FinalScore  int32                  `protobuf:"varint,1,opt,name=final_score,json=finalScore,proto3" json:"final_score,omitempty"`
+	Multiplayer bool                   `protobuf:"varint,2,opt,name=multiplayer,proto3" json:"multiplayer,omitempty"`
+	Finished    bool                   `protobuf:"varint,3,opt,name=finished,proto3" json:"finished,omitempty"`
+	// Only set for multiplayer games
+	Players       []*PlayerScore      `protobuf:"bytes,4,rep,name=players,proto3" json:"players,omitempty"`
+	Leaderboard   []*LeaderboardEntry `protobuf:"bytes,5,rep,name=leaderboard,proto3" json:"leaderboard,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EndGameResponse) Reset() {
+	*x = EndGameResponse{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndGameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndGameResponse) ProtoMessage() {}
+
+func (x *EndGameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndGameResponse.ProtoReflect.Descriptor instead.
+func (*EndGameResponse) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *EndGameResponse) GetFinalScore() int32 {
+	if x != nil {
+		return x.FinalScore
+	}
+	return 0
+}
+
+func (x *EndGameResponse) GetMultiplayer() bool {
+	if x != nil {
+		return x.Multiplayer
+	}
+	return false
+}
+
+func (x *EndGameResponse) GetFinished() bool {
+	if x != nil {
+		return x.Finished
+	}
+	return false
+}
+
+func (x *EndGameResponse) GetPlayers() []*PlayerScore {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *EndGameResponse) GetLeaderboard() []*LeaderboardEntry {
+	if x != nil {
+		return x.Leaderboard
+	}
+	return nil
+}
+
+type StreamEventsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	GameId string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	// Session of the player, the stream ends when the session is removed from the game
+	SessionId     string `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *StreamEventsRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *StreamEventsRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+// Players of the room with their scores, sent with allPlayers, scoreUpdate and gameFinished
+type PlayerScores struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Players       []*PlayerScore         `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerScores) Reset() {
+	*x = PlayerScores{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerScores) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerScores) ProtoMessage() {}
+
+func (x *PlayerScores) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerScores.ProtoReflect.Descriptor instead.
+func (*PlayerScores) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PlayerScores) GetPlayers() []*PlayerScore {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+// Player that joined the room or used a power-up
+type PlayerEvent struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Name      string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SessionId string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// Only set for powerUpUsed
+	PowerUp       string `protobuf:"bytes,3,opt,name=power_up,json=powerUp,proto3" json:"power_up,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerEvent) Reset() {
+	*x = PlayerEvent{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerEvent) ProtoMessage() {}
+
+func (x *PlayerEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerEvent.ProtoReflect.Descriptor instead.
+func (*PlayerEvent) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PlayerEvent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PlayerEvent) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *PlayerEvent) GetPowerUp() string {
+	if x != nil {
+		return x.PowerUp
+	}
+	return ""
+}
+
+// Player the owner kicked or banned
+type PlayerRemoved struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Banned        bool                   `protobuf:"varint,3,opt,name=banned,proto3" json:"banned,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerRemoved) Reset() {
+	*x = PlayerRemoved{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerRemoved) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerRemoved) ProtoMessage() {}
+
+func (x *PlayerRemoved) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerRemoved.ProtoReflect.Descriptor instead.
+func (*PlayerRemoved) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PlayerRemoved) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PlayerRemoved) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *PlayerRemoved) GetBanned() bool {
+	if x != nil {
+		return x.Banned
+	}
+	return false
+}
+
+type Countdown struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SecondsLeft   int32                  `protobuf:"varint,1,opt,name=seconds_left,json=secondsLeft,proto3" json:"seconds_left,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Countdown) Reset() {
+	*x = Countdown{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Countdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Countdown) ProtoMessage() {}
+
+func (x *Countdown) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Countdown.ProtoReflect.Descriptor instead.
+func (*Countdown) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Countdown) GetSecondsLeft() int32 {
+	if x != nil {
+		return x.SecondsLeft
+	}
+	return 0
+}
+
+type GameStarted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameStarted) Reset() {
+	*x = GameStarted{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameStarted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameStarted) ProtoMessage() {}
+
+func (x *GameStarted) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameStarted.ProtoReflect.Descriptor instead.
+func (*GameStarted) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GameStarted) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// Event is a message of the game room, the same message the websocket sends with its content typed
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// allPlayers, playerJoined, playerRemoved, startGameCountdown, startGame, scoreUpdate, powerUpUsed or gameFinished
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id   string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*Event_Players
+	//	*Event_Player
+	//	*Event_Removed
+	//	*Event_Countdown
+	//	*Event_Started
+	Payload       isEvent_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_captrivia_v1_game_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_captrivia_v1_game_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_captrivia_v1_game_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Event) GetPayload() isEvent_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Event) GetPlayers() *PlayerScores {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Players); ok {
+			return x.Players
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetPlayer() *PlayerEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Player); ok {
+			return x.Player
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetRemoved() *PlayerRemoved {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Removed); ok {
+			return x.Removed
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetCountdown() *Countdown {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Countdown); ok {
+			return x.Countdown
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetStarted() *GameStarted {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Started); ok {
+			return x.Started
+		}
+	}
+	return nil
+}
+
+type isEvent_Payload interface {
+	isEvent_Payload()
+}
+
+type Event_Players struct {
+	// allPlayers, scoreUpdate and gameFinished
+	Players *PlayerScores `protobuf:"bytes,4,opt,name=players,proto3,oneof"`
+}
+
+type Event_Player struct {
+	// playerJoined and powerUpUsed
+	Player *PlayerEvent `protobuf:"bytes,5,opt,name=player,proto3,oneof"`
+}
+
+type Event_Removed struct {
+	// playerRemoved
+	Removed *PlayerRemoved `protobuf:"bytes,6,opt,name=removed,proto3,oneof"`
+}
+
+type Event_Countdown struct {
+	// startGameCountdown
+	Countdown *Countdown `protobuf:"bytes,7,opt,name=countdown,proto3,oneof"`
+}
+
+type Event_Started struct {
+	// startGame
+	Started *GameStarted `protobuf:"bytes,8,opt,name=started,proto3,oneof"`
+}
+
+func (*Event_Players) isEvent_Payload() {}
+
+func (*Event_Player) isEvent_Payload() {}
+
+func (*Event_Removed) isEvent_Payload() {}
+
+func (*Event_Countdown) isEvent_Payload() {}
+
+func (*Event_Started) isEvent_Payload() {}
+
+var File_captrivia_v1_game_proto protoreflect.FileDescriptor
+
+const file_captrivia_v1_game_proto_rawDesc = "" +
+	"\n" +
+	"\x17captrivia/v1/game.proto\x12\fcaptrivia.v1\"\x9c\x03\n" +
+	"\x10StartGameRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vmultiplayer\x18\x03 \x01(\bR\vmultiplayer\x12\x1c\n" +
+	"\tquestions\x18\x04 \x01(\x05R\tquestions\x12\x16\n" +
+	"\x06public\x18\x05 \x01(\bR\x06public\x12\x1a\n" +
+	"\bcapacity\x18\x06 \x01(\x05R\bcapacity\x12\x1a\n" +
+	"\bcategory\x18\a \x01(\tR\bcategory\x12\x1f\n" +
+	"\vname_policy\x18\b \x01(\tR\n" +
+	"namePolicy\x12\x12\n" +
+	"\x04mode\x18\t \x01(\tR\x04mode\x12I\n" +
+	"\tpower_ups\x18\n" +
+	" \x03(\v2,.captrivia.v1.StartGameRequest.PowerUpsEntryR\bpowerUps\x12\x16\n" +
+	"\x06locale\x18\v \x01(\tR\x06locale\x1a;\n" +
+	"\rPowerUpsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01J\x04\b\x02\x10\x03R\tplayer_id\"h\n" +
+	"\x11StartGameResponse\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x1b\n" +
+	"\tplayer_id\x18\x03 \x01(\tR\bplayerId\"g\n" +
+	"\x0fJoinGameRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06locale\x18\x04 \x01(\tR\x06localeJ\x04\b\x03\x10\x04R\tplayer_id\"{\n" +
+	"\x10JoinGameResponse\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x1b\n" +
+	"\tplayer_id\x18\x03 \x01(\tR\bplayerId\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\"H\n" +
+	"\x0eGetGameRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\"\xa7\x01\n" +
+	"\bQuestion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12#\n" +
+	"\rquestion_text\x18\x03 \x01(\tR\fquestionText\x12\x18\n" +
+	"\aoptions\x18\x04 \x03(\tR\aoptions\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12\x1c\n" +
+	"\ttolerance\x18\x06 \x01(\x01R\ttolerance\")\n" +
+	"\rOptionIndexes\x12\x18\n" +
+	"\aindexes\x18\x01 \x03(\x05R\aindexes\"\xa5\x05\n" +
+	"\x0fGetGameResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\astarted\x18\x02 \x01(\bR\astarted\x12\x1a\n" +
+	"\bfinished\x18\x03 \x01(\bR\bfinished\x12 \n" +
+	"\vmultiplayer\x18\x04 \x01(\bR\vmultiplayer\x12\x12\n" +
+	"\x04mode\x18\x05 \x01(\tR\x04mode\x124\n" +
+	"\tquestions\x18\x06 \x03(\v2\x16.captrivia.v1.QuestionR\tquestions\x12%\n" +
+	"\x0equestion_index\x18\a \x01(\x05R\rquestionIndex\x12#\n" +
+	"\rcurrent_score\x18\b \x01(\x05R\fcurrentScore\x12H\n" +
+	"\tpower_ups\x18\t \x03(\v2+.captrivia.v1.GetGameResponse.PowerUpsEntryR\bpowerUps\x12#\n" +
+	"\rdouble_points\x18\n" +
+	" \x01(\bR\fdoublePoints\x12Z\n" +
+	"\x0fremoved_options\x18\v \x03(\v21.captrivia.v1.GetGameResponse.RemovedOptionsEntryR\x0eremovedOptions\x12\x16\n" +
+	"\x06locale\x18\f \x01(\tR\x06locale\x12\x14\n" +
+	"\x05owner\x18\r \x01(\bR\x05owner\x1a;\n" +
+	"\rPowerUpsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1a^\n" +
+	"\x13RemovedOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x121\n" +
+	"\x05value\x18\x02 \x01(\v2\x1b.captrivia.v1.OptionIndexesR\x05value:\x028\x01\"\xce\x01\n" +
+	"\x06Answer\x12\x18\n" +
+	"\x06option\x18\x01 \x01(\x05H\x00R\x06option\x12\x19\n" +
+	"\ais_true\x18\x02 \x01(\bH\x00R\x06isTrue\x127\n" +
+	"\aoptions\x18\x03 \x01(\v2\x1b.captrivia.v1.OptionIndexesH\x00R\aoptions\x12\x18\n" +
+	"\x06number\x18\x04 \x01(\x01H\x00R\x06number\x123\n" +
+	"\x05order\x18\x05 \x01(\v2\x1b.captrivia.v1.OptionIndexesH\x00R\x05orderB\a\n" +
+	"\x05value\"\x9c\x01\n" +
+	"\x13SubmitAnswerRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x1f\n" +
+	"\vquestion_id\x18\x03 \x01(\tR\n" +
+	"questionId\x12,\n" +
+	"\x06answer\x18\x04 \x01(\v2\x14.captrivia.v1.AnswerR\x06answer\"\xc8\x01\n" +
+	"\x14SubmitAnswerResponse\x12)\n" +
+	"\x10already_answered\x18\x01 \x01(\bR\x0falreadyAnswered\x12\x18\n" +
+	"\acorrect\x18\x02 \x01(\bR\acorrect\x12\x16\n" +
+	"\x06credit\x18\x03 \x01(\x01R\x06credit\x12#\n" +
+	"\rcurrent_score\x18\x04 \x01(\x05R\fcurrentScore\x12.\n" +
+	"\x13next_question_index\x18\x05 \x01(\x05R\x11nextQuestionIndex\"H\n" +
+	"\x0eEndGameRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\"V\n" +
+	"\vPlayerScore\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x05R\x05score\"\xa4\x02\n" +
+	"\x10LeaderboardEntry\x12\x12\n" +
+	"\x04rank\x18\x01 \x01(\x05R\x04rank\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12!\n" +
+	"\fgames_played\x18\x04 \x01(\x05R\vgamesPlayed\x12\x18\n" +
+	"\acorrect\x18\x05 \x01(\x05R\acorrect\x12\x1a\n" +
+	"\banswered\x18\x06 \x01(\x05R\banswered\x12\x1a\n" +
+	"\baccuracy\x18\a \x01(\x01R\baccuracy\x12\x1d\n" +
+	"\n" +
+	"best_score\x18\b \x01(\x05R\tbestScore\x12\x1f\n" +
+	"\vtotal_score\x18\t \x01(\x05R\n" +
+	"totalScore\x12\x16\n" +
+	"\x06rating\x18\n" +
+	" \x01(\x01R\x06rating\"\xe7\x01\n" +
+	"\x0fEndGameResponse\x12\x1f\n" +
+	"\vfinal_score\x18\x01 \x01(\x05R\n" +
+	"finalScore\x12 \n" +
+	"\vmultiplayer\x18\x02 \x01(\bR\vmultiplayer\x12\x1a\n" +
+	"\bfinished\x18\x03 \x01(\bR\bfinished\x123\n" +
+	"\aplayers\x18\x04 \x03(\v2\x19.captrivia.v1.PlayerScoreR\aplayers\x12@\n" +
+	"\vleaderboard\x18\x05 \x03(\v2\x1e.captrivia.v1.LeaderboardEntryR\vleaderboard\"M\n" +
+	"\x13StreamEventsRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\"C\n" +
+	"\fPlayerScores\x123\n" +
+	"\aplayers\x18\x01 \x03(\v2\x19.captrivia.v1.PlayerScoreR\aplayers\"[\n" +
+	"\vPlayerEvent\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x19\n" +
+	"\bpower_up\x18\x03 \x01(\tR\apowerUp\"Z\n" +
+	"\rPlayerRemoved\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x16\n" +
+	"\x06banned\x18\x03 \x01(\bR\x06banned\".\n" +
+	"\tCountdown\x12!\n" +
+	"\fseconds_left\x18\x01 \x01(\x05R\vsecondsLeft\"'\n" +
+	"\vGameStarted\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\xdb\x02\n" +
+	"\x05Event\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x126\n" +
+	"\aplayers\x18\x04 \x01(\v2\x1a.captrivia.v1.PlayerScoresH\x00R\aplayers\x123\n" +
+	"\x06player\x18\x05 \x01(\v2\x19.captrivia.v1.PlayerEventH\x00R\x06player\x127\n" +
+	"\aremoved\x18\x06 \x01(\v2\x1b.captrivia.v1.PlayerRemovedH\x00R\aremoved\x127\n" +
+	"\tcountdown\x18\a \x01(\v2\x17.captrivia.v1.CountdownH\x00R\tcountdown\x125\n" +
+	"\astarted\x18\b \x01(\v2\x19.captrivia.v1.GameStartedH\x00R\astartedB\t\n" +
+	"\apayloadJ\x04\b\x02\x10\x03R\acontent2\xd7\x03\n" +
+	"\vGameService\x12L\n" +
+	"\tStartGame\x12\x1e.captrivia.v1.StartGameRequest\x1a\x1f.captrivia.v1.StartGameResponse\x12I\n" +
+	"\bJoinGame\x12\x1d.captrivia.v1.JoinGameRequest\x1a\x1e.captrivia.v1.JoinGameResponse\x12F\n" +
+	"\aGetGame\x12\x1c.captrivia.v1.GetGameRequest\x1a\x1d.captrivia.v1.GetGameResponse\x12U\n" +
+	"\fSubmitAnswer\x12!.captrivia.v1.SubmitAnswerRequest\x1a\".captrivia.v1.SubmitAnswerResponse\x12F\n" +
+	"\aEndGame\x12\x1c.captrivia.v1.EndGameRequest\x1a\x1d.captrivia.v1.EndGameResponse\x12H\n" +
+	"\fStreamEvents\x12!.captrivia.v1.StreamEventsRequest\x1a\x13.captrivia.v1.Event0\x01B@Z>github.com/ProlificLabs/captrivia/gen/captrivia/v1;captriviav1b\x06proto3"
+
+var (
+	file_captrivia_v1_game_proto_rawDescOnce sync.Once
+	file_captrivia_v1_game_proto_rawDescData []byte
+)
+
+func file_captrivia_v1_game_proto_rawDescGZIP() []byte {
+	file_captrivia_v1_game_proto_rawDescOnce.Do(func() {
+		file_captrivia_v1_game_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_captrivia_v1_game_proto_rawDesc), len(file_captrivia_v1_game_proto_rawDesc)))
+	})
+	return file_captrivia_v1_game_proto_rawDescData
+}
+
+var file_captrivia_v1_game_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_captrivia_v1_game_proto_goTypes = []any{
+	(*StartGameRequest)(nil),     // 0: captrivia.v1.StartGameRequest
+	(*StartGameResponse)(nil),    // 1: captrivia.v1.StartGameResponse
+	(*JoinGameRequest)(nil),      // 2: captrivia.v1.JoinGameRequest
+	(*JoinGameResponse)(nil),     // 3: captrivia.v1.JoinGameResponse
+	(*GetGameRequest)(nil),       // 4: captrivia.v1.GetGameRequest
+	(*Question)(nil),             // 5: captrivia.v1.Question
+	(*OptionIndexes)(nil),        // 6: captrivia.v1.OptionIndexes
+	(*GetGameResponse)(nil),      // 7: captrivia.v1.GetGameResponse
+	(*Answer)(nil),               // 8: captrivia.v1.Answer
+	(*SubmitAnswerRequest)(nil),  // 9: captrivia.v1.SubmitAnswerRequest
+	(*SubmitAnswerResponse)(nil), // 10: captrivia.v1.SubmitAnswerResponse
+	(*EndGameRequest)(nil),       // 11: captrivia.v1.EndGameRequest
+	(*PlayerScore)(nil),          // 12: captrivia.v1.PlayerScore
+	(*LeaderboardEntry)(nil),     // 13: captrivia.v1.LeaderboardEntry
+	(*EndGameResponse)(nil),      // 14: captrivia.v1.EndGameResponse
+	(*StreamEventsRequest)(nil),  // 15: captrivia.v1.StreamEventsRequest
+	(*PlayerScores)(nil),         // 16: captrivia.v1.PlayerScores
+	(*PlayerEvent)(nil),          // 17: captrivia.v1.PlayerEvent
+	(*PlayerRemoved)(nil),        // 18: captrivia.v1.PlayerRemoved
+	(*Countdown)(nil),            // 19: captrivia.v1.Countdown
+	(*GameStarted)(nil),          // 20: captrivia.v1.GameStarted
+	(*Event)(nil),                // 21: captrivia.v1.Event
+	nil,                          // 22: captrivia.v1.StartGameRequest.PowerUpsEntry
+	nil,                          // 23: captrivia.v1.GetGameResponse.PowerUpsEntry
+	nil,                          // 24: captrivia.v1.GetGameResponse.RemovedOptionsEntry
+}
+var file_captrivia_v1_game_proto_depIdxs = []int32{
+	22, // 0: captrivia.v1.StartGameRequest.power_ups:type_name -> captrivia.v1.StartGameRequest.PowerUpsEntry
+	5,  // 1: captrivia.v1.GetGameResponse.questions:type_name -> captrivia.v1.Question
+	23, // 2: captrivia.v1.GetGameResponse.power_ups:type_name -> captrivia.v1.GetGameResponse.PowerUpsEntry
+	24, // 3: captrivia.v1.GetGameResponse.removed_options:type_name -> captrivia.v1.GetGameResponse.RemovedOptionsEntry
+	6,  // 4: captrivia.v1.Answer.options:type_name -> captrivia.v1.OptionIndexes
+	6,  // 5: captrivia.v1.Answer.order:type_name -> captrivia.v1.OptionIndexes
+	8,  // 6: captrivia.v1.SubmitAnswerRequest.answer:type_name -> captrivia.v1.Answer
+	12, // 7: captrivia.v1.EndGameResponse.players:type_name -> captrivia.v1.PlayerScore
+	13, // 8: captrivia.v1.EndGameResponse.leaderboard:type_name -> captrivia.v1.LeaderboardEntry
+	12, // 9: captrivia.v1.PlayerScores.players:type_name -> captrivia.v1.PlayerScore
+	16, // 10: captrivia.v1.Event.players:type_name -> captrivia.v1.PlayerScores
+	17, // 11: captrivia.v1.Event.player:type_name -> captrivia.v1.PlayerEvent
+	18, // 12: captrivia.v1.Event.removed:type_name -> captrivia.v1.PlayerRemoved
+	19, // 13: captrivia.v1.Event.countdown:type_name -> captrivia.v1.Countdown
+	20, // 14: captrivia.v1.Event.started:type_name -> captrivia.v1.GameStarted
+	6,  // 15: captrivia.v1.GetGameResponse.RemovedOptionsEntry.value:type_name -> captrivia.v1.OptionIndexes
+	0,  // 16: captrivia.v1.GameService.StartGame:input_type -> captrivia.v1.StartGameRequest
+	2,  // 17: captrivia.v1.GameService.JoinGame:input_type -> captrivia.v1.JoinGameRequest
+	4,  // 18: captrivia.v1.GameService.GetGame:input_type -> captrivia.v1.GetGameRequest
+	9,  // 19: captrivia.v1.GameService.SubmitAnswer:input_type -> captrivia.v1.SubmitAnswerRequest
+	11, // 20: captrivia.v1.GameService.EndGame:input_type -> captrivia.v1.EndGameRequest
+	15, // 21: captrivia.v1.GameService.StreamEvents:input_type -> captrivia.v1.StreamEventsRequest
+	1,  // 22: captrivia.v1.GameService.StartGame:output_type -> captrivia.v1.StartGameResponse
+	3,  // 23: captrivia.v1.GameService.JoinGame:output_type -> captrivia.v1.JoinGameResponse
+	7,  // 24: captrivia.v1.GameService.GetGame:output_type -> captrivia.v1.GetGameResponse
+	10, // 25: captrivia.v1.GameService.SubmitAnswer:output_type -> captrivia.v1.SubmitAnswerResponse
+	14, // 26: captrivia.v1.GameService.EndGame:output_type -> captrivia.v1.EndGameResponse
+	21, // 27: captrivia.v1.GameService.StreamEvents:output_type -> captrivia.v1.Event
+	22, // [22:28] is the sub-list for method output_type
+	16, // [16:22] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_captrivia_v1_game_proto_init() }
+func file_captrivia_v1_game_proto_init() {
+	if File_captrivia_v1_game_proto != nil {
+		return
+	}
+	file_captrivia_v1_game_proto_msgTypes[8].OneofWrappers = []any{
+		(*Answer_Option)(nil),
+		(*Answer_IsTrue)(nil),
+		(*Answer_Options)(nil),
+		(*Answer_Number)(nil),
+		(*Answer_Order)(nil),
+	}
+	file_captrivia_v1_game_proto_msgTypes[21].OneofWrappers = []any{
+		(*Event_Players)(nil),
+		(*Event_Player)(nil),
+		(*Event_Removed)(nil),
+		(*Event_Countdown)(nil),
+		(*Event_Started)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_captrivia_v1_game_proto_rawDesc), len(file_captrivia_v1_game_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_captrivia_v1_game_proto_goTypes,
+		DependencyIndexes: file_captrivia_v1_game_proto_depIdxs,
+		MessageInfos:      file_captrivia_v1_game_proto_msgTypes,
+	}.Build()
+	File_captrivia_v1_game_proto = out.File
+	file_captrivia_v1_game_proto_goTypes = nil
+	file_captrivia_v1_game_proto_depIdxs = nil
+}
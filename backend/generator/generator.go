@@ -0,0 +1,285 @@
+// Package generator builds cap table scenario questions from random parameters.
+// Every question is built from a seed, so it can be built again from its ID for review.
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/models"
+)
+
+// Category of the generated questions
+const Category = "scenarios"
+
+// Number of questions generated every time a game asks for the category
+const DefaultBatchSize = 20
+
+// Prefix of the ID of every generated question, followed by the scenario and the seed
+const idPrefix = "generated-"
+
+type Scenario string
+
+const (
+	// Ownership of new investors after a priced round
+	ScenarioPricedRound Scenario = "priced-round"
+	// Effective pre-money valuation once an option pool is carved out before the round
+	ScenarioOptionPool Scenario = "option-pool"
+	// Ownership a SAFE converts into with a valuation cap and a discount
+	ScenarioSAFE Scenario = "safe"
+	// Payout of an investor with a non-participating liquidation preference
+	ScenarioLiquidationPreference Scenario = "liquidation-preference"
+)
+
+// Scenarios lists every kind of question the generator builds
+var Scenarios = []Scenario{ScenarioPricedRound, ScenarioOptionPool, ScenarioSAFE, ScenarioLiquidationPreference}
+
+var ErrInvalidQuestionID = errors.New("not the ID of a generated question")
+
+// scenario is a computed question before its options are formatted and shuffled
+type scenario struct {
+	text        string
+	explanation string
+	answer      float64
+	// Answers from common mistakes, some may be the same as the answer and are skipped
+	distractors []float64
+	// Options at or above it give themselves away and are skipped, 0 when there is no limit
+	limit  float64
+	format func(float64) string
+}
+
+var builders = map[Scenario]func(random *rand.Rand) scenario{
+	ScenarioPricedRound:           pricedRound,
+	ScenarioOptionPool:            optionPool,
+	ScenarioSAFE:                  safe,
+	ScenarioLiquidationPreference: liquidationPreference,
+}
+
+// QuestionID returns the ID of the question built for the scenario from the seed
+func QuestionID(kind Scenario, seed int64) string {
+	return idPrefix + string(kind) + "-" + strconv.FormatInt(seed, 10)
+}
+
+// Generate builds the question of the scenario for the seed, the same seed always gives the same question
+func Generate(kind Scenario, seed int64) (models.Question, error) {
+	build, exists := builders[kind]
+	if !exists {
+		return models.Question{}, fmt.Errorf("unknown scenario %s", kind)
+	}
+
+	random := rand.New(rand.NewSource(seed))
+	built := build(random)
+	options, correctIndex := buildOptions(built, random)
+
+	return models.Question{
+		ID:           QuestionID(kind, seed),
+		Category:     Category,
+		QuestionText: built.text,
+		Options:      options,
+		CorrectIndex: correctIndex,
+		Explanation:  built.explanation,
+		Seed:         seed,
+	}, nil
+}
+
+// Regenerate builds a generated question again from its ID
+func Regenerate(questionID string) (models.Question, error) {
+	if !strings.HasPrefix(questionID, idPrefix) {
+		return models.Question{}, ErrInvalidQuestionID
+	}
+
+	rest := strings.TrimPrefix(questionID, idPrefix)
+	separator := strings.LastIndex(rest, "-")
+	if separator < 0 {
+		return models.Question{}, ErrInvalidQuestionID
+	}
+
+	seed, err := strconv.ParseInt(rest[separator+1:], 10, 64)
+	if err != nil {
+		return models.Question{}, ErrInvalidQuestionID
+	}
+	return Generate(Scenario(rest[:separator]), seed)
+}
+
+// buildOptions formats the answer and three distinct distractors and shuffles them
+func buildOptions(built scenario, random *rand.Rand) ([]string, int) {
+	answer := built.format(built.answer)
+	seen := map[string]bool{answer: true}
+	options := []string{answer}
+
+	// Fall back to answers a bit off when the mistakes give the same result
+	candidates := append(built.distractors, built.answer*0.8, built.answer*1.25, built.answer*0.6, built.answer*1.5)
+	for _, candidate := range candidates {
+		if len(options) == 4 {
+			break
+		}
+		option := built.format(candidate)
+		if candidate < 0 || (built.limit > 0 && candidate >= built.limit) || seen[option] {
+			continue
+		}
+		seen[option] = true
+		options = append(options, option)
+	}
+
+	random.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+	for i, option := range options {
+		if option == answer {
+			return options, i
+		}
+	}
+	return options, 0
+}
+
+// Source generates a new batch of scenario questions whenever a game asks for the category
+type Source struct {
+	lock      sync.Mutex
+	random    *rand.Rand
+	BatchSize int
+}
+
+func NewSource() *Source {
+	return &Source{random: rand.New(rand.NewSource(time.Now().UnixNano())), BatchSize: DefaultBatchSize}
+}
+
+// Questions only returns questions for the generated category, so games of every other category stay the same
+func (source *Source) Questions(category string) ([]models.Question, error) {
+	if category != Category {
+		return nil, nil
+	}
+
+	source.lock.Lock()
+	defer source.lock.Unlock()
+
+	questions := make([]models.Question, 0, source.BatchSize)
+	for i := 0; i < source.BatchSize; i++ {
+		question, err := Generate(Scenarios[i%len(Scenarios)], source.random.Int63())
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, question)
+	}
+	return questions, nil
+}
+
+// randomStep returns a random multiple of step between min and max
+func randomStep(random *rand.Rand, min float64, max float64, step float64) float64 {
+	steps := int((max - min) / step)
+	return min + float64(random.Intn(steps+1))*step
+}
+
+func formatMoney(value float64) string {
+	return fmt.Sprintf("$%.2fM", value/1e6)
+}
+
+func formatPercent(value float64) string {
+	return fmt.Sprintf("%.1f%%", value)
+}
+
+func pricedRound(random *rand.Rand) scenario {
+	preMoney := randomStep(random, 4e6, 40e6, 0.5e6)
+	investment := randomStep(random, 1e6, 15e6, 0.5e6)
+	postMoney := preMoney + investment
+
+	return scenario{
+		text: fmt.Sprintf("A startup raises %s at a %s pre-money valuation. What percentage of the company do the new investors own after the round?",
+			formatMoney(investment), formatMoney(preMoney)),
+		explanation: fmt.Sprintf("The post-money valuation is %s + %s = %s, so the investors own %s / %s = %s.",
+			formatMoney(preMoney), formatMoney(investment), formatMoney(postMoney), formatMoney(investment), formatMoney(postMoney), formatPercent(investment/postMoney*100)),
+		answer: investment / postMoney * 100,
+		distractors: []float64{
+			// Dividing by the pre-money instead of the post-money valuation
+			investment / preMoney * 100,
+			// The ownership left to the existing shareholders
+			preMoney / postMoney * 100,
+			investment / (postMoney + investment) * 100,
+		},
+		limit:  100,
+		format: formatPercent,
+	}
+}
+
+func optionPool(random *rand.Rand) scenario {
+	preMoney := randomStep(random, 8e6, 40e6, 1e6)
+	investment := randomStep(random, 2e6, 15e6, 0.5e6)
+	pool := randomStep(random, 5, 20, 1)
+	postMoney := preMoney + investment
+	poolValue := pool / 100 * postMoney
+
+	return scenario{
+		text: fmt.Sprintf("Investors offer %s at a %s pre-money valuation, on the condition that an option pool of %.0f%% of the post-money company is created before the round. What is the effective pre-money valuation for the existing shareholders?",
+			formatMoney(investment), formatMoney(preMoney), pool),
+		explanation: fmt.Sprintf("The pool is %.0f%% of the %s post-money valuation, %s, and it comes out of the pre-money valuation: %s - %s = %s.",
+			pool, formatMoney(postMoney), formatMoney(poolValue), formatMoney(preMoney), formatMoney(poolValue), formatMoney(preMoney-poolValue)),
+		answer: preMoney - poolValue,
+		distractors: []float64{
+			// Ignoring the option pool
+			preMoney,
+			// Sizing the pool on the pre-money valuation
+			preMoney - pool/100*preMoney,
+			// Taking the pool out of the post-money valuation
+			postMoney - poolValue,
+		},
+		format: formatMoney,
+	}
+}
+
+func safe(random *rand.Rand) scenario {
+	// The valuation the SAFE converts at is at least $7M, so the largest amount buys about a fifth of the company
+	amount := randomStep(random, 0.25e6, 1.5e6, 0.25e6)
+	valuationCap := randomStep(random, 8e6, 20e6, 1e6)
+	discount := randomStep(random, 10, 30, 5)
+	roundValuation := randomStep(random, 10e6, 40e6, 1e6)
+	discounted := roundValuation * (1 - discount/100)
+	conversion := math.Min(valuationCap, discounted)
+
+	return scenario{
+		text: fmt.Sprintf("An investor puts %s into a SAFE with a %s valuation cap and a %.0f%% discount. The company later raises a priced round at a %s valuation. The SAFE converts at the lower of the cap and the discounted round valuation. Ignoring other dilution, what percentage of the company does the SAFE convert into?",
+			formatMoney(amount), formatMoney(valuationCap), discount, formatMoney(roundValuation)),
+		explanation: fmt.Sprintf("The discounted round valuation is %s, so the SAFE converts at min(%s, %s) = %s and owns %s / %s = %s.",
+			formatMoney(discounted), formatMoney(valuationCap), formatMoney(discounted), formatMoney(conversion), formatMoney(amount), formatMoney(conversion), formatPercent(amount/conversion*100)),
+		answer: amount / conversion * 100,
+		distractors: []float64{
+			// Always using the cap
+			amount / valuationCap * 100,
+			// Always using the discount
+			amount / discounted * 100,
+			// Ignoring both the cap and the discount
+			amount / roundValuation * 100,
+			amount / (valuationCap + amount) * 100,
+		},
+		limit:  100,
+		format: formatPercent,
+	}
+}
+
+func liquidationPreference(random *rand.Rand) scenario {
+	investment := randomStep(random, 2e6, 20e6, 1e6)
+	ownership := randomStep(random, 10, 40, 5)
+	exit := randomStep(random, 2e6, 100e6, 1e6)
+	preference := math.Min(investment, exit)
+	converted := ownership / 100 * exit
+	payout := math.Max(preference, converted)
+
+	return scenario{
+		text: fmt.Sprintf("An investor put %s into a company for %.0f%% of it, with a 1x non-participating liquidation preference. The company is sold for %s. How much does the investor receive?",
+			formatMoney(investment), ownership, formatMoney(exit)),
+		explanation: fmt.Sprintf("The investor takes the larger of the preference, %s, and converting to common for %.0f%% of %s = %s, so they receive %s.",
+			formatMoney(preference), ownership, formatMoney(exit), formatMoney(converted), formatMoney(payout)),
+		answer: payout,
+		distractors: []float64{
+			preference,
+			converted,
+			// Treating the preference as participating
+			math.Min(exit, preference+ownership/100*math.Max(exit-investment, 0)),
+			// What is left for everyone else
+			exit - payout,
+		},
+		format: formatMoney,
+	}
+}
@@ -0,0 +1,254 @@
+// Package i18n holds the translations of the messages the server sends to players
+package i18n
+
+import "strings"
+
+// DefaultLocale is used when a message or question has no translation for the requested locale
+const DefaultLocale = "en"
+
+// Key identifies a message of the catalog
+type Key string
+
+const (
+	InvalidRequestBody    Key = "invalidRequestBody"
+	GameNotFound          Key = "gameNotFound"
+	SessionNotFound       Key = "sessionNotFound"
+	InvalidSessionID      Key = "invalidSessionId"
+	QuestionNotFound      Key = "questionNotFound"
+	PlayerNotFound        Key = "playerNotFound"
+	GameFinished          Key = "gameFinished"
+	GameFull              Key = "gameFull"
+	SinglePlayerGame      Key = "singlePlayerGame"
+	NoQuestionForPowerUp  Key = "noQuestionForPowerUp"
+	QuestionSkipped       Key = "questionSkipped"
+	OptionRemoved         Key = "optionRemoved"
+	ReviewNotAvailable    Key = "reviewNotAvailable"
+	OwnerOnly             Key = "ownerOnly"
+	OwnerCannotBeRemoved  Key = "ownerCannotBeRemoved"
+	OrganizerOnly         Key = "organizerOnly"
+	InvalidWindow         Key = "invalidWindow"
+	InvalidSort           Key = "invalidSort"
+	InvalidDate           Key = "invalidDate"
+	InvalidParameter      Key = "invalidParameter"
+	InternalError         Key = "internalError"
+	ValidationFailed      Key = "validationFailed"
+	TicketNotFound        Key = "ticketNotFound"
+	TicketExpired         Key = "ticketExpired"
+	TicketReplaced        Key = "ticketReplaced"
+	TournamentNotFound    Key = "tournamentNotFound"
+	CategoryNotFound      Key = "categoryNotFound"
+	NameRequired          Key = "nameRequired"
+	NameTooLong           Key = "nameTooLong"
+	NameInvalidCharacters Key = "nameInvalidCharacters"
+	NameNotAllowed        Key = "nameNotAllowed"
+	NameTaken             Key = "nameTaken"
+	PlayerBanned          Key = "playerBanned"
+	InvalidAnswer         Key = "invalidAnswer"
+	PowerUpUsedUp         Key = "powerUpUsedUp"
+	DoublePointsActive    Key = "doublePointsActive"
+	NotEnoughWrongOptions Key = "notEnoughWrongOptions"
+	FiftyFiftyUnsupported Key = "fiftyFiftyUnsupported"
+	DailyAttemptUsed      Key = "dailyAttemptUsed"
+	DailyNeedsIdentity    Key = "dailyNeedsIdentity"
+	TournamentStarted     Key = "tournamentStarted"
+	AlreadyRegistered     Key = "alreadyRegistered"
+	NotEnoughPlayers      Key = "notEnoughPlayers"
+	MatchNotFound         Key = "matchNotFound"
+	NotTournamentPlayer   Key = "notTournamentPlayer"
+
+	// Reasons a field failed validation, they follow the name of the field
+	FieldRequired      Key = "fieldRequired"
+	FieldTooSmall      Key = "fieldTooSmall"
+	FieldTooLarge      Key = "fieldTooLarge"
+	FieldQuestionCount Key = "fieldQuestionCount"
+	FieldInvalid       Key = "fieldInvalid"
+)
+
+// Messages of every supported locale, the default locale must have every key
+var catalog = map[string]map[Key]string{
+	"en": {
+		InvalidRequestBody:    "Invalid request body",
+		GameNotFound:          "Game not found",
+		SessionNotFound:       "Session not found",
+		InvalidSessionID:      "Invalid session ID",
+		QuestionNotFound:      "Question not found",
+		PlayerNotFound:        "Player not found",
+		GameFinished:          "Game has already finished",
+		GameFull:              "Game is full",
+		SinglePlayerGame:      "Cannot join a single player game",
+		NoQuestionForPowerUp:  "No question left to use a power-up on",
+		QuestionSkipped:       "Question was skipped",
+		OptionRemoved:         "Option was removed by 50/50",
+		ReviewNotAvailable:    "Review is available once you have finished the game",
+		OwnerOnly:             "Only the game owner can remove players",
+		OwnerCannotBeRemoved:  "The game owner cannot be removed",
+		OrganizerOnly:         "Only the tournament organizer can do this",
+		InvalidWindow:         "invalid window",
+		InvalidSort:           "invalid sort",
+		InvalidDate:           "invalid date",
+		InvalidParameter:      "Invalid query parameter",
+		InternalError:         "Something went wrong, please try again",
+		ValidationFailed:      "Some fields of the request are not valid",
+		TicketNotFound:        "Matchmaking ticket not found",
+		TicketExpired:         "No match was found before the ticket expired",
+		TicketReplaced:        "You joined the queue again with another ticket",
+		TournamentNotFound:    "Tournament not found",
+		CategoryNotFound:      "No questions found for this category",
+		NameRequired:          "Name is required",
+		NameTooLong:           "Name is too long",
+		NameInvalidCharacters: "Name contains invalid characters",
+		NameNotAllowed:        "Name is not allowed",
+		NameTaken:             "Name is already taken in this game",
+		PlayerBanned:          "You are banned from this game",
+		InvalidAnswer:         "Answer does not fit the question type",
+		PowerUpUsedUp:         "No uses of this power-up left",
+		DoublePointsActive:    "Double points is already active",
+		NotEnoughWrongOptions: "The question does not have enough wrong options",
+		FiftyFiftyUnsupported: "50/50 only works on single choice questions",
+		DailyAttemptUsed:      "You have already played today's challenge",
+		DailyNeedsIdentity:    "The daily challenge needs the player ID the server issued you",
+		FieldRequired:         "is required",
+		FieldTooSmall:         "must be at least %s",
+		FieldTooLarge:         "must be at most %s",
+		FieldQuestionCount:    "must be between 1 and %d",
+		FieldInvalid:          "is not valid",
+		TournamentStarted:     "Tournament has already started",
+		AlreadyRegistered:     "Player is already registered",
+		NotEnoughPlayers:      "At least two players are needed to start the tournament",
+		MatchNotFound:         "Tournament match not found",
+		NotTournamentPlayer:   "You are not a player in this tournament",
+	},
+	"es": {
+		InvalidRequestBody:    "Cuerpo de la solicitud no válido",
+		GameNotFound:          "Partida no encontrada",
+		SessionNotFound:       "Sesión no encontrada",
+		InvalidSessionID:      "ID de sesión no válido",
+		QuestionNotFound:      "Pregunta no encontrada",
+		PlayerNotFound:        "Jugador no encontrado",
+		GameFinished:          "La partida ya ha terminado",
+		GameFull:              "La partida está llena",
+		SinglePlayerGame:      "No se puede unir a una partida de un solo jugador",
+		NoQuestionForPowerUp:  "No quedan preguntas en las que usar un comodín",
+		QuestionSkipped:       "La pregunta se ha saltado",
+		OptionRemoved:         "La opción fue eliminada por el 50/50",
+		ReviewNotAvailable:    "La revisión estará disponible cuando termines la partida",
+		OwnerOnly:             "Solo el creador de la partida puede expulsar jugadores",
+		OwnerCannotBeRemoved:  "No se puede expulsar al creador de la partida",
+		OrganizerOnly:         "Solo el organizador del torneo puede hacer esto",
+		InvalidWindow:         "periodo no válido",
+		InvalidSort:           "orden no válido",
+		InvalidDate:           "fecha no válida",
+		InvalidParameter:      "Parámetro de consulta no válido",
+		InternalError:         "Algo salió mal, inténtalo de nuevo",
+		ValidationFailed:      "Algunos campos de la solicitud no son válidos",
+		TicketNotFound:        "Ticket de emparejamiento no encontrado",
+		TicketExpired:         "No se encontró rival antes de que caducara el ticket",
+		TicketReplaced:        "Volviste a entrar en la cola con otro ticket",
+		TournamentNotFound:    "Torneo no encontrado",
+		CategoryNotFound:      "No hay preguntas para esta categoría",
+		NameRequired:          "El nombre es obligatorio",
+		NameTooLong:           "El nombre es demasiado largo",
+		NameInvalidCharacters: "El nombre contiene caracteres no válidos",
+		NameNotAllowed:        "Ese nombre no está permitido",
+		NameTaken:             "El nombre ya está en uso en esta partida",
+		PlayerBanned:          "Tienes prohibida la entrada a esta partida",
+		InvalidAnswer:         "La respuesta no corresponde al tipo de pregunta",
+		PowerUpUsedUp:         "No quedan usos de este comodín",
+		DoublePointsActive:    "Los puntos dobles ya están activos",
+		NotEnoughWrongOptions: "La pregunta no tiene suficientes opciones incorrectas",
+		FiftyFiftyUnsupported: "El 50/50 solo funciona en preguntas de opción única",
+		DailyAttemptUsed:      "Ya has jugado el desafío de hoy",
+		DailyNeedsIdentity:    "El desafío diario necesita el ID de jugador que te asignó el servidor",
+		FieldRequired:         "es obligatorio",
+		FieldTooSmall:         "debe ser al menos %s",
+		FieldTooLarge:         "debe ser como máximo %s",
+		FieldQuestionCount:    "debe estar entre 1 y %d",
+		FieldInvalid:          "no es válido",
+		TournamentStarted:     "El torneo ya ha empezado",
+		AlreadyRegistered:     "El jugador ya está inscrito",
+		NotEnoughPlayers:      "Se necesitan al menos dos jugadores para empezar el torneo",
+		MatchNotFound:         "Enfrentamiento del torneo no encontrado",
+		NotTournamentPlayer:   "No participas en este torneo",
+	},
+	"fr": {
+		InvalidRequestBody:    "Corps de la requête invalide",
+		GameNotFound:          "Partie introuvable",
+		SessionNotFound:       "Session introuvable",
+		InvalidSessionID:      "ID de session invalide",
+		QuestionNotFound:      "Question introuvable",
+		PlayerNotFound:        "Joueur introuvable",
+		GameFinished:          "La partie est déjà terminée",
+		GameFull:              "La partie est complète",
+		SinglePlayerGame:      "Impossible de rejoindre une partie solo",
+		NoQuestionForPowerUp:  "Il ne reste aucune question sur laquelle utiliser un bonus",
+		QuestionSkipped:       "La question a été passée",
+		OptionRemoved:         "L'option a été retirée par le 50/50",
+		ReviewNotAvailable:    "La correction sera disponible une fois la partie terminée",
+		OwnerOnly:             "Seul le créateur de la partie peut retirer des joueurs",
+		OwnerCannotBeRemoved:  "Le créateur de la partie ne peut pas être retiré",
+		OrganizerOnly:         "Seul l'organisateur du tournoi peut faire cela",
+		InvalidWindow:         "période invalide",
+		InvalidSort:           "tri invalide",
+		InvalidDate:           "date invalide",
+		InvalidParameter:      "Paramètre de requête invalide",
+		InternalError:         "Une erreur est survenue, veuillez réessayer",
+		ValidationFailed:      "Certains champs de la requête sont invalides",
+		TicketNotFound:        "Ticket de matchmaking introuvable",
+		TicketExpired:         "Aucun adversaire n'a été trouvé avant l'expiration du ticket",
+		TicketReplaced:        "Vous avez rejoint la file d'attente avec un autre ticket",
+		TournamentNotFound:    "Tournoi introuvable",
+		CategoryNotFound:      "Aucune question trouvée pour cette catégorie",
+		NameRequired:          "Le nom est obligatoire",
+		NameTooLong:           "Le nom est trop long",
+		NameInvalidCharacters: "Le nom contient des caractères invalides",
+		NameNotAllowed:        "Ce nom n'est pas autorisé",
+		NameTaken:             "Ce nom est déjà pris dans cette partie",
+		PlayerBanned:          "Vous êtes banni de cette partie",
+		InvalidAnswer:         "La réponse ne correspond pas au type de question",
+		PowerUpUsedUp:         "Il ne reste plus d'utilisation de ce bonus",
+		DoublePointsActive:    "Les points doublés sont déjà actifs",
+		NotEnoughWrongOptions: "La question n'a pas assez de mauvaises réponses",
+		FiftyFiftyUnsupported: "Le 50/50 ne fonctionne que sur les questions à choix unique",
+		DailyAttemptUsed:      "Vous avez déjà joué le défi du jour",
+		DailyNeedsIdentity:    "Le défi du jour nécessite l'identifiant de joueur attribué par le serveur",
+		FieldRequired:         "est obligatoire",
+		FieldTooSmall:         "doit être au moins %s",
+		FieldTooLarge:         "doit être au plus %s",
+		FieldQuestionCount:    "doit être entre 1 et %d",
+		FieldInvalid:          "n'est pas valide",
+		TournamentStarted:     "Le tournoi a déjà commencé",
+		AlreadyRegistered:     "Le joueur est déjà inscrit",
+		NotEnoughPlayers:      "Il faut au moins deux joueurs pour lancer le tournoi",
+		MatchNotFound:         "Match du tournoi introuvable",
+		NotTournamentPlayer:   "Vous ne participez pas à ce tournoi",
+	},
+}
+
+// Normalize lowercases a locale and uses dashes between its parts, e.g. "pt_BR" becomes "pt-br"
+func Normalize(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+}
+
+// Fallbacks lists the locales to try for a locale, from the most to the least specific,
+// e.g. "fr-ca", "fr" and then the default locale
+func Fallbacks(locale string) []string {
+	locale = Normalize(locale)
+	fallbacks := make([]string, 0, 3)
+	if locale != "" {
+		fallbacks = append(fallbacks, locale)
+		if language, _, found := strings.Cut(locale, "-"); found {
+			fallbacks = append(fallbacks, language)
+		}
+	}
+	return append(fallbacks, DefaultLocale)
+}
+
+// Message returns the message in the locale, or in the closest locale the catalog has
+func Message(locale string, key Key) string {
+	for _, candidate := range Fallbacks(locale) {
+		if message, exists := catalog[candidate][key]; exists {
+			return message
+		}
+	}
+	return string(key)
+}
@@ -3,7 +3,11 @@ package main
 import (
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/ProlificLabs/captrivia/controllers"
+	"github.com/ProlificLabs/captrivia/models"
 	"github.com/ProlificLabs/captrivia/routes"
 
 	"github.com/gin-contrib/cors"
@@ -33,15 +37,80 @@ func main() {
 func setupServer() (*gin.Engine, error) {
 	// Create Gin router and setup routes
 	router := gin.Default()
+	// gRPC clients talk HTTP/2 without TLS
+	router.UseH2C = true
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 	config := cors.DefaultConfig()
-	// allow all origins
-	config.AllowAllOrigins = true
+	// Players are identified by a cookie, so the browser has to send credentials with cross-origin requests.
+	// Set ALLOWED_ORIGINS to the comma separated origins of the frontend, only the local frontend is allowed when it is not set.
+	config.AllowCredentials = true
+	config.AllowOriginFunc = allowedOrigin(os.Getenv("ALLOWED_ORIGINS"))
 	router.Use(cors.New(config))
+	// Handlers report failures with c.Error, this renders them with a stable error code
+	router.Use(controllers.ErrorMiddleware())
+	// Request bodies are checked with the binding tags of the request structs
+	if err := controllers.RegisterValidators(); err != nil {
+		return nil, err
+	}
 
-	routes.GameRoutes(router)
-	routes.AnswerRoutes(router)
+	// Names containing any of these words are rejected, set NAME_DENY_LIST to use another file
+	denyListPath := os.Getenv("NAME_DENY_LIST")
+	if denyListPath == "" {
+		denyListPath = "name_denylist.txt"
+	}
+	if err := models.LoadNameDenyList(denyListPath); err != nil {
+		return nil, err
+	}
+
+	// Player stats are saved under DATA_DIR so they survive restarts
+	dataDir := os.Getenv("DATA_DIR")
+	if dataDir == "" {
+		dataDir = "data"
+	}
+	if err := models.Leaderboards.Load(filepath.Join(dataDir, "leaderboard.json")); err != nil {
+		return nil, err
+	}
+	if err := models.Ratings.Load(filepath.Join(dataDir, "ratings.json")); err != nil {
+		return nil, err
+	}
+	if err := models.GameArchive.Load(filepath.Join(dataDir, "games.json")); err != nil {
+		return nil, err
+	}
+	if err := models.Repetitions.Load(filepath.Join(dataDir, "repetitions.json")); err != nil {
+		return nil, err
+	}
+	if err := models.DailyChallenges.Load(filepath.Join(dataDir, "daily.json")); err != nil {
+		return nil, err
+	}
+	// The secret signing player identities has to survive restarts, or every player gets a new ID
+	if err := models.PlayerIdentities.Load(filepath.Join(dataDir, "identity.json")); err != nil {
+		return nil, err
+	}
+
+	// Everything is served under /api/v1, the unversioned paths are kept for older clients
+	routes.APIRoutes(router)
+	routes.RPCRoutes(router)
 
 	return router, nil
+}
+
+// Origin of the frontend started with npm start or docker compose
+const localFrontendOrigin = "http://localhost:3000"
+
+// allowedOrigin returns the CORS check of a comma separated list of origins, an empty list allows the local frontend
+func allowedOrigin(origins string) func(string) bool {
+	if strings.TrimSpace(origins) == "" {
+		origins = localFrontendOrigin
+	}
+	allowed := make(map[string]bool)
+	for _, origin := range strings.Split(origins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			allowed[origin] = true
+		}
+	}
+
+	return func(origin string) bool {
+		return allowed[origin]
+	}
 }
\ No newline at end of file
@@ -1,18 +1,33 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"connectrpc.com/connect"
+	"github.com/ProlificLabs/captrivia/controllers"
+	captriviav1 "github.com/ProlificLabs/captrivia/gen/captrivia/v1"
+	"github.com/ProlificLabs/captrivia/gen/captrivia/v1/captriviav1connect"
+	"github.com/ProlificLabs/captrivia/generator"
 	"github.com/ProlificLabs/captrivia/models"
+	"github.com/ProlificLabs/captrivia/routes"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 )
 
 var testRouter *gin.Engine
@@ -24,7 +39,13 @@ func TestMain(m *testing.M) {
 	// Set Gin to test mode so that it doesn't print out debug info and we can use testing shortcuts
 	gin.SetMode(gin.TestMode)
 
-	var err error
+	// Keep saved player stats out of the working directory
+	dataDir, err := os.MkdirTemp("", "captrivia-test")
+	if err != nil {
+		log.Fatal("Failed to create data directory:", err)
+	}
+	os.Setenv("DATA_DIR", dataDir)
+
 	testRouter, err = setupServer() // This should call the same setupServer which is used in main.
 	if err != nil {
 		log.Fatal("Failed to set up test server:", err)
@@ -35,8 +56,9 @@ func TestMain(m *testing.M) {
 
 	runTests := m.Run()
 
-	// Close the test server
+	// Close the test server and remove the saved data
 	testServer.Close()
+	os.RemoveAll(dataDir)
 
 	// Exit with the result of the test suite run
 	os.Exit(runTests)
@@ -143,7 +165,7 @@ func TestFullGame(t *testing.T) {
 		t.Fatalf("No questions received")
 	}
 
-	// Answer each question (assuming the answer is always the first option)
+	// Answer each question with a guess in the shape of its type
 	for _, question := range questions {
 		// Make sure we haven't been given the answer.  We're using the same struct here for the server-side
 		// handler and the "client", so if it wasn't set it should always be 0
@@ -151,7 +173,8 @@ func TestFullGame(t *testing.T) {
 			t.Fatalf("Backend returned answer index")
 		}
 
-		answerPayload := fmt.Sprintf(`{"gameId":"%s","sessionId":"%s", "questionId":"%s", "answer":%d}`, gameID, sessionID, question.ID, 0)
+		answer, _ := json.Marshal(guessAnswer(question))
+		answerPayload := fmt.Sprintf(`{"gameId":"%s","sessionId":"%s", "questionId":"%s", "answer":%s}`, gameID, sessionID, question.ID, answer)
 		answerReader := strings.NewReader(answerPayload)
 		resp, err = http.Post(testServer.URL+"/answer", "application/json", answerReader)
 		if err != nil {
@@ -199,3 +222,2344 @@ func TestFullGame(t *testing.T) {
 		t.Errorf("Response should contain 'finalScore' field")
 	}
 }
+
+// guessAnswer returns an answer in the shape of the question type, without knowing the correct one
+func guessAnswer(question models.Question) interface{} {
+	switch question.Kind() {
+	case models.QuestionTrueFalse:
+		return true
+	case models.QuestionMultiSelect:
+		return []int{0}
+	case models.QuestionNumeric:
+		return 0.0
+	case models.QuestionOrdering:
+		order := make([]int, len(question.Options))
+		for i := range order {
+			order[i] = i
+		}
+		return order
+	default:
+		return 0
+	}
+}
+
+// wrongAnswer returns an answer in the shape of the question type that earns no credit
+func wrongAnswer(question models.Question) interface{} {
+	switch question.Kind() {
+	case models.QuestionTrueFalse:
+		return !question.IsTrue
+	case models.QuestionMultiSelect:
+		// Only the options that are not correct, none when every option is correct
+		picked := make([]int, 0, len(question.Options))
+		for i := range question.Options {
+			correct := false
+			for _, index := range question.CorrectIndexes {
+				correct = correct || index == i
+			}
+			if !correct {
+				picked = append(picked, i)
+			}
+		}
+		return picked
+	case models.QuestionNumeric:
+		return question.CorrectNumber + question.Tolerance + 1
+	case models.QuestionOrdering:
+		order := make([]int, len(question.CorrectOrder))
+		for i, index := range question.CorrectOrder {
+			order[len(order)-1-i] = index
+		}
+		return order
+	default:
+		return (question.CorrectIndex + 1) % len(question.Options)
+	}
+}
+
+// newPlayerClient returns a client that keeps the identity cookie it is issued, like the browser of one player
+func newPlayerClient(t *testing.T) *http.Client {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("Failed to create a cookie jar: %v", err)
+	}
+	return &http.Client{Jar: jar}
+}
+
+func startGame(t *testing.T, payload map[string]interface{}) map[string]string {
+	return startGameAs(t, http.DefaultClient, payload)
+}
+
+func startGameAs(t *testing.T, client *http.Client, payload map[string]interface{}) map[string]string {
+	body, _ := json.Marshal(payload)
+	resp, err := client.Post(testServer.URL+"/game/start", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status OK; got %v", resp.Status)
+	}
+
+	var response map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	return response
+}
+
+// test that only public multiplayer games are listed in the game browser
+func TestListGamesHandler(t *testing.T) {
+	publicGame := startGame(t, map[string]interface{}{"name": "Billy Bob", "multiplayer": true, "questions": 3, "public": true, "capacity": 4, "category": "basics"})
+	privateGame := startGame(t, map[string]interface{}{"name": "Billy Bob", "multiplayer": true, "questions": 3, "category": "basics"})
+
+	resp, err := http.Get(testServer.URL + "/games?category=basics&questions=3")
+	if err != nil {
+		t.Fatalf("Failed to list games: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status OK; got %v", resp.Status)
+	}
+
+	var response struct {
+		Games []models.Lobby `json:"games"`
+		Total int            `json:"total"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+
+	var found *models.Lobby
+	for i, game := range response.Games {
+		if game.ID == privateGame["gameId"] {
+			t.Errorf("Private game should not be listed")
+		}
+		if game.ID == publicGame["gameId"] {
+			found = &response.Games[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("Public game should be listed")
+	}
+	if found.Players != 1 || found.Capacity != 4 || found.Questions != 3 || found.Category != "basics" {
+		t.Errorf("Unexpected lobby listing: %+v", *found)
+	}
+
+	resp, err = http.Get(testServer.URL + "/games?limit=abc")
+	if err != nil {
+		t.Fatalf("Failed to list games: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status Bad Request; got %v", resp.Status)
+	}
+}
+
+func postJSON(t *testing.T, path string, payload map[string]interface{}) (int, map[string]interface{}) {
+	return postJSONAs(t, http.DefaultClient, path, payload)
+}
+
+func postJSONAs(t *testing.T, client *http.Client, path string, payload map[string]interface{}) (int, map[string]interface{}) {
+	body, _ := json.Marshal(payload)
+	resp, err := client.Post(testServer.URL+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to post to %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	return resp.StatusCode, response
+}
+
+// test that invalid names are rejected when starting and joining games
+func TestPlayerNameValidation(t *testing.T) {
+	names := []string{"", "   ", "<script>alert(1)</script>", strings.Repeat("a", models.MaxPlayerNameLength+1), "The Admin", "admin_42"}
+	for _, name := range names {
+		status, _ := postJSON(t, "/game/start", map[string]interface{}{"name": name, "multiplayer": true, "questions": 3})
+		if status != http.StatusBadRequest {
+			t.Errorf("Expected starting a game as %q to fail; got %v", name, status)
+		}
+	}
+
+	game := startGame(t, map[string]interface{}{"name": "Billy Bob", "multiplayer": true, "questions": 3})
+	status, _ := postJSON(t, "/game/join", map[string]interface{}{"gameId": game["gameId"], "name": "System"})
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected joining with a denied name to fail; got %v", status)
+	}
+
+	// Only whole words are denied
+	for _, name := range []string{"Badminton", "Systematic Sam"} {
+		if err := models.ValidatePlayerName(name); err != nil {
+			t.Errorf("Expected %q to be allowed; got %v", name, err)
+		}
+	}
+}
+
+// test that the owner can kick and ban players
+func TestKickAndBanPlayer(t *testing.T) {
+	game := startGame(t, map[string]interface{}{"name": "Billy Bob", "multiplayer": true, "questions": 3})
+	gameID := game["gameId"]
+
+	jimmy := newPlayerClient(t)
+	_, joined := postJSONAs(t, jimmy, "/game/join", map[string]interface{}{"gameId": gameID, "name": "Jimmy"})
+	jimmySession := joined["sessionId"]
+
+	// Only the owner may kick players
+	status, _ := postJSON(t, "/game/kick", map[string]interface{}{"gameId": gameID, "sessionId": jimmySession, "targetSessionId": game["sessionId"]})
+	if status != http.StatusForbidden {
+		t.Errorf("Expected kick by a non-owner to be forbidden; got %v", status)
+	}
+
+	status, _ = postJSON(t, "/game/kick", map[string]interface{}{"gameId": gameID, "sessionId": game["sessionId"], "targetSessionId": jimmySession})
+	if status != http.StatusOK {
+		t.Fatalf("Expected kick to succeed; got %v", status)
+	}
+	if _, exists := models.GameServers[gameID].Sessions.GetSession(jimmySession.(string)); exists {
+		t.Errorf("Kicked session should have been deleted")
+	}
+
+	// A kicked player can join again, a banned one cannot
+	_, joined = postJSONAs(t, jimmy, "/game/join", map[string]interface{}{"gameId": gameID, "name": "Jimmy"})
+	status, _ = postJSON(t, "/game/ban", map[string]interface{}{"gameId": gameID, "sessionId": game["sessionId"], "targetSessionId": joined["sessionId"]})
+	if status != http.StatusOK {
+		t.Fatalf("Expected ban to succeed; got %v", status)
+	}
+
+	status, _ = postJSONAs(t, jimmy, "/game/join", map[string]interface{}{"gameId": gameID, "name": "Jimmy"})
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected banned player to be rejected; got %v", status)
+	}
+
+	// The ban follows the identity the server issued, not the name or an ID in the body
+	status, _ = postJSON(t, "/game/join", map[string]interface{}{"gameId": gameID, "name": "Jimmy", "playerId": joined["playerId"]})
+	if status != http.StatusOK {
+		t.Errorf("Expected another player to join; got %v", status)
+	}
+}
+
+// test that duplicate names are suffixed or rejected depending on the game's name policy
+func TestUniqueNamesInGame(t *testing.T) {
+	game := startGame(t, map[string]interface{}{"name": "Billy Bob", "multiplayer": true, "questions": 3})
+	status, joined := postJSON(t, "/game/join", map[string]interface{}{"gameId": game["gameId"], "name": "  billy   BOB "})
+	if status != http.StatusOK {
+		t.Fatalf("Expected join to succeed; got %v", status)
+	}
+	if joined["name"] != "billy BOB 2" {
+		t.Errorf("Expected duplicate name to be suffixed; got %v", joined["name"])
+	}
+
+	// A suffixed name is still a valid name, even when the name was already as long as allowed
+	longName := strings.Repeat("x", models.MaxPlayerNameLength)
+	game = startGame(t, map[string]interface{}{"name": longName, "multiplayer": true, "questions": 3})
+	for _, expected := range []string{longName[:models.MaxPlayerNameLength-2] + " 2", longName[:models.MaxPlayerNameLength-2] + " 3"} {
+		status, joined = postJSON(t, "/game/join", map[string]interface{}{"gameId": game["gameId"], "name": longName})
+		if status != http.StatusOK || joined["name"] != expected {
+			t.Fatalf("Expected the name %q; got %v %v", expected, status, joined["name"])
+		}
+		if err := models.ValidatePlayerName(expected); err != nil {
+			t.Errorf("Expected the suffixed name to be valid: %v", err)
+		}
+	}
+
+	game = startGame(t, map[string]interface{}{"name": "Billy Bob", "multiplayer": true, "questions": 3, "namePolicy": "reject"})
+	status, _ = postJSON(t, "/game/join", map[string]interface{}{"gameId": game["gameId"], "name": "billy bob"})
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected duplicate name to be rejected; got %v", status)
+	}
+
+	status, _ = postJSON(t, "/game/start", map[string]interface{}{"name": "Billy Bob", "multiplayer": true, "questions": 3, "namePolicy": "random"})
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected unknown name policy to be rejected; got %v", status)
+	}
+}
+
+// test that finished games are recorded on the leaderboard under the player's ID
+func TestLeaderboard(t *testing.T) {
+	larry := newPlayerClient(t)
+	game := startGameAs(t, larry, map[string]interface{}{"name": "Leaderboard Larry", "questions": 2})
+	gameServer := models.GameServers[game["gameId"]]
+	for _, question := range gameServer.Questions {
+		status, _ := postJSON(t, "/answer", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "questionId": question.ID, "answer": question.Solution()})
+		if status != http.StatusOK {
+			t.Fatalf("Expected status OK; got %v", status)
+		}
+	}
+
+	for _, window := range []string{"all-time", "weekly", "daily"} {
+		// The player's own entry is found from their identity cookie
+		resp, err := larry.Get(testServer.URL + "/leaderboard?window=" + window)
+		if err != nil {
+			t.Fatalf("Failed to get leaderboard: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status OK; got %v", resp.Status)
+		}
+
+		var response struct {
+			Entries []models.LeaderboardEntry `json:"entries"`
+			Player  *models.LeaderboardEntry  `json:"player"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode JSON response: %v", err)
+		}
+
+		if response.Player == nil {
+			t.Fatalf("Expected the player's own entry in the %s leaderboard", window)
+		}
+		if response.Player.GamesPlayed != 1 || response.Player.Accuracy != 100 || response.Player.BestScore != 20 || response.Player.Rank < 1 {
+			t.Errorf("Unexpected %s leaderboard entry: %+v", window, *response.Player)
+		}
+	}
+
+	// Another player's ID in the query does not reveal their rank
+	_, others := getJSON(t, "/leaderboard?playerId="+game["playerId"])
+	if others["player"] != nil {
+		t.Errorf("Expected no player entry without the player's identity; got %v", others["player"])
+	}
+
+	resp, err := http.Get(testServer.URL + "/leaderboard?window=monthly")
+	if err != nil {
+		t.Fatalf("Failed to get leaderboard: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status Bad Request; got %v", resp.Status)
+	}
+}
+
+// test that the leaderboard only keeps the buckets of the current daily and weekly windows
+func TestLeaderboardWindowsArePruned(t *testing.T) {
+	leaderboard := models.NewLeaderboard()
+	monday := time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC)
+	for day := 0; day < 10; day++ {
+		result := models.GameResult{PlayerID: "player", Name: "Pruned Pete", Score: 10, Correct: 1, Answered: 1}
+		if err := leaderboard.Record(result, monday.AddDate(0, 0, day)); err != nil {
+			t.Fatalf("Failed to record result: %v", err)
+		}
+	}
+
+	if len(leaderboard.Buckets) != 3 {
+		t.Errorf("Expected the all-time, weekly and daily buckets only; got %d buckets", len(leaderboard.Buckets))
+	}
+	lastDay := monday.AddDate(0, 0, 9)
+	if entries := leaderboard.Ranked(models.LeaderboardAllTime, models.SortByScore, lastDay, models.NewRatingStore()); len(entries) != 1 || entries[0].GamesPlayed != 10 {
+		t.Errorf("Unexpected all-time entries: %+v", entries)
+	}
+	if entries := leaderboard.Ranked(models.LeaderboardWeekly, models.SortByScore, lastDay, models.NewRatingStore()); len(entries) != 1 || entries[0].GamesPlayed != 3 {
+		t.Errorf("Unexpected weekly entries: %+v", entries)
+	}
+}
+
+// test that ratings are updated once from the final scores of a multiplayer game
+func TestMultiplayerRatings(t *testing.T) {
+	game := startGame(t, map[string]interface{}{"name": "Winner", "multiplayer": true, "questions": 2})
+	_, joined := postJSON(t, "/game/join", map[string]interface{}{"gameId": game["gameId"], "name": "Loser"})
+
+	gameServer := models.GameServers[game["gameId"]]
+	for _, question := range gameServer.Questions {
+		postJSON(t, "/answer", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "questionId": question.ID, "answer": question.Solution()})
+		postJSON(t, "/answer", map[string]interface{}{"gameId": game["gameId"], "sessionId": joined["sessionId"], "questionId": question.ID, "answer": wrongAnswer(question)})
+	}
+
+	for _, sessionID := range []interface{}{game["sessionId"], joined["sessionId"], joined["sessionId"]} {
+		status, _ := postJSON(t, "/game/end", map[string]interface{}{"gameId": game["gameId"], "sessionId": sessionID})
+		if status != http.StatusOK {
+			t.Fatalf("Expected status OK; got %v", status)
+		}
+	}
+
+	for playerID, higher := range map[string]bool{game["playerId"]: true, joined["playerId"].(string): false} {
+		resp, err := http.Get(testServer.URL + "/ratings/" + playerID)
+		if err != nil {
+			t.Fatalf("Failed to get rating: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var rating models.PlayerRating
+		if err := json.NewDecoder(resp.Body).Decode(&rating); err != nil {
+			t.Fatalf("Failed to decode JSON response: %v", err)
+		}
+
+		if len(rating.History) != 1 {
+			t.Fatalf("Expected one rated game for %s; got %d", playerID, len(rating.History))
+		}
+		if (rating.Rating > models.InitialRating) != higher {
+			t.Errorf("Unexpected rating for %s: %v", playerID, rating.Rating)
+		}
+	}
+}
+
+// test that a full queue is moved into a new multiplayer game
+func TestMatchmaking(t *testing.T) {
+	ticketIDs := make([]string, 0, models.MatchMaxPlayers)
+	for i := 0; i < models.MatchMaxPlayers; i++ {
+		status, ticket := postJSON(t, "/matchmaking/enqueue", map[string]interface{}{"name": fmt.Sprintf("Quick %d", i), "questions": 4, "category": "securities"})
+		if status != http.StatusOK {
+			t.Fatalf("Expected status OK; got %v", status)
+		}
+		ticketIDs = append(ticketIDs, ticket["ticketId"].(string))
+	}
+
+	// The matchmaker runs every second
+	deadline := time.Now().Add(3 * time.Second)
+	gameIDs := make(map[string]bool)
+	for _, ticketID := range ticketIDs {
+		for {
+			resp, err := http.Get(testServer.URL + "/matchmaking/" + ticketID)
+			if err != nil {
+				t.Fatalf("Failed to get ticket: %v", err)
+			}
+			var ticket map[string]interface{}
+			err = json.NewDecoder(resp.Body).Decode(&ticket)
+			resp.Body.Close()
+			if err != nil {
+				t.Fatalf("Failed to decode JSON response: %v", err)
+			}
+
+			if ticket["status"] == "matched" {
+				gameIDs[ticket["gameId"].(string)] = true
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Ticket was not matched in time: %v", ticket)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	if len(gameIDs) != 1 {
+		t.Fatalf("Expected all players in one game; got %d games", len(gameIDs))
+	}
+	for gameID := range gameIDs {
+		if count := models.GameServers[gameID].Sessions.Count(); count != models.MatchMaxPlayers {
+			t.Errorf("Expected %d players in the game; got %d", models.MatchMaxPlayers, count)
+		}
+	}
+}
+
+// test that tickets taken for a failed match go back to the front of their queue and that waiting tickets expire
+func TestMatchmakingRequeueAndExpiry(t *testing.T) {
+	queue := models.NewMatchmakingQueue()
+	preferences := models.MatchPreferences{Questions: 4}
+	first, _ := queue.Enqueue("first", "First", preferences, time.Now())
+	second, _ := queue.Enqueue("second", "Second", preferences, time.Now())
+
+	taken := queue.TakeMatches(time.Now().Add(models.MatchWaitTimeout))
+	if len(taken) != 1 || len(taken[0]) != 2 {
+		t.Fatalf("Expected both tickets to be matched; got %v", taken)
+	}
+	third, _ := queue.Enqueue("third", "Third", preferences, time.Now())
+	queue.Requeue(taken[0])
+	queue.UpdatePositions()
+	for position, ticketID := range []string{first.ID, second.ID, third.ID} {
+		if ticket, _ := queue.GetTicket(ticketID); ticket.Position != position+1 {
+			t.Errorf("Expected ticket %d to be at position %d; got %d", position+1, position+1, ticket.Position)
+		}
+	}
+
+	expired := queue.RemoveExpired(time.Now().Add(models.WaitingTicketTTL + time.Second))
+	if len(expired) != 3 || !errors.Is(expired[0].Failure, models.ErrTicketExpired) {
+		t.Fatalf("Expected every waiting ticket to expire; got %+v", expired)
+	}
+	if _, exists := queue.GetTicket(first.ID); exists {
+		t.Errorf("Expected the expired ticket to be removed")
+	}
+}
+
+// test that a player waits with one ticket at a time and is never matched against themselves
+func TestMatchmakingOneTicketPerPlayer(t *testing.T) {
+	queue := models.NewMatchmakingQueue()
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	first, _ := queue.Enqueue("twin", "Twin", models.MatchPreferences{Questions: 4}, now)
+	second, replaced := queue.Enqueue("twin", "Twin", models.MatchPreferences{Questions: 5}, now)
+	if len(replaced) != 1 || replaced[0].ID != first.ID || !errors.Is(replaced[0].Failure, models.ErrTicketReplaced) {
+		t.Fatalf("Expected the first ticket to be replaced; got %+v", replaced)
+	}
+	if ticket, _ := queue.GetTicket(first.ID); ticket.Failure == nil {
+		t.Errorf("Expected the replaced ticket to be closed")
+	}
+	if second.Enqueued != now {
+		t.Errorf("Expected the ticket to be enqueued at %v; got %v", now, second.Enqueued)
+	}
+
+	// Tickets that went back in line can still hold the same player twice, they are matched apart
+	preferences := models.MatchPreferences{Questions: 5}
+	queue.Tickets["stale"] = &models.Ticket{ID: "stale", PlayerID: "twin", Preferences: preferences, Enqueued: now}
+	queue.Queues[preferences] = append(queue.Queues[preferences], queue.Tickets["stale"])
+	if matches := queue.TakeMatches(now.Add(models.MatchWaitTimeout)); len(matches) != 0 {
+		t.Errorf("Expected no match of a player against themselves; got %+v", matches)
+	}
+
+	queue.Enqueue("other", "Other", preferences, now)
+	matches := queue.TakeMatches(now.Add(models.MatchWaitTimeout))
+	if len(matches) != 1 || len(matches[0]) != 2 || matches[0][0].PlayerID == matches[0][1].PlayerID {
+		t.Fatalf("Expected the player to be matched with the other player; got %+v", matches)
+	}
+}
+
+// test that finished games show up in the player's profile and match history
+func TestPlayerProfileAndHistory(t *testing.T) {
+	game := startGame(t, map[string]interface{}{"name": "History Harry", "questions": 2})
+	gameServer := models.GameServers[game["gameId"]]
+	for i, question := range gameServer.Questions {
+		// Answer the first question correctly and the second one wrong
+		answer := question.Solution()
+		if i == 1 {
+			answer = wrongAnswer(question)
+		}
+		postJSON(t, "/answer", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "questionId": question.ID, "answer": answer})
+	}
+	postJSON(t, "/game/end", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"]})
+
+	resp, err := http.Get(testServer.URL + "/players/" + game["playerId"])
+	if err != nil {
+		t.Fatalf("Failed to get profile: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var profile models.PlayerProfile
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	if profile.GamesPlayed != 1 || profile.Accuracy != 50 || profile.Name != "History Harry" || len(profile.AccuracyTrend) != 1 {
+		t.Errorf("Unexpected profile: %+v", profile)
+	}
+
+	resp, err = http.Get(testServer.URL + "/players/" + game["playerId"] + "/games")
+	if err != nil {
+		t.Fatalf("Failed to get match history: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var history struct {
+		Games []models.PlayerGame `json:"games"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	if len(history.Games) != 1 || history.Games[0].GameID != game["gameId"] {
+		t.Fatalf("Unexpected match history: %+v", history.Games)
+	}
+
+	answers := history.Games[0].Answers
+	if len(answers) != 2 || !answers[0].Correct || answers[1].Correct || answers[0].QuestionText == "" {
+		t.Errorf("Unexpected answers: %+v", answers)
+	}
+
+	resp, err = http.Get(testServer.URL + "/players/nobody")
+	if err != nil {
+		t.Fatalf("Failed to get profile: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status Not Found; got %v", resp.Status)
+	}
+}
+
+// test that the archive drops its oldest games once it holds more than its limit
+func TestGameArchiveLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.json")
+	archive := models.NewGameArchiveStore()
+	archive.Limit = 2
+	if err := archive.Load(path); err != nil {
+		t.Fatalf("Failed to load archive: %v", err)
+	}
+	for _, gameID := range []string{"first", "second", "third", "fourth", "fifth"} {
+		sessions := models.NewSessionStore(models.NamePolicySuffix)
+		sessions.CreateSession("Archived Annie", "archived-player")
+		gameServer := &models.GameServer{ID: gameID, Sessions: sessions, Finished: time.Now()}
+		if err := archive.Archive(gameServer); err != nil {
+			t.Fatalf("Failed to archive game: %v", err)
+		}
+	}
+
+	games := archive.GamesOfPlayer("archived-player")
+	if len(games) != 2 || games[0].GameID != "fifth" || games[1].GameID != "fourth" {
+		t.Errorf("Expected the two newest games; got %+v", games)
+	}
+
+	// Games are appended to the file, the dropped ones are removed from it once they take up half of it
+	fileBytes, _ := os.ReadFile(path)
+	if lines := strings.Count(string(fileBytes), "\n"); lines < 2 || lines > 3 {
+		t.Errorf("Expected the file to be compacted; got %d lines", lines)
+	}
+	reloaded := models.NewGameArchiveStore()
+	reloaded.Limit = 2
+	if err := reloaded.Load(path); err != nil {
+		t.Fatalf("Failed to reload archive: %v", err)
+	}
+	if games := reloaded.GamesOfPlayer("archived-player"); len(games) != 2 || games[0].GameID != "fifth" {
+		t.Errorf("Expected the two newest games after a restart; got %+v", games)
+	}
+
+	// Archives saved as a single object are still read, and rewritten a game per line
+	legacyPath := filepath.Join(t.TempDir(), "games.json")
+	os.WriteFile(legacyPath, []byte(`{"games":[{"id":"legacy","players":[{"playerId":"legacy-player"}]}]}`), 0644)
+	legacy := models.NewGameArchiveStore()
+	if err := legacy.Load(legacyPath); err != nil {
+		t.Fatalf("Failed to load legacy archive: %v", err)
+	}
+	if games := legacy.GamesOfPlayer("legacy-player"); len(games) != 1 || games[0].GameID != "legacy" {
+		t.Errorf("Expected the legacy game; got %+v", games)
+	}
+	if fileBytes, _ := os.ReadFile(legacyPath); !strings.HasPrefix(string(fileBytes), `{"id":"legacy"`) {
+		t.Errorf("Expected the legacy archive to be rewritten a game per line; got %s", fileBytes)
+	}
+}
+
+// test that the review reveals answers and explanations only once the session has finished
+func TestGameReview(t *testing.T) {
+	// The shipped questions are translated, the review is in the locale of the session
+	game := startGame(t, map[string]interface{}{"name": "Review Rita", "questions": 2, "locale": "es"})
+	reviewURL := testServer.URL + "/game/" + game["gameId"] + "/" + game["sessionId"] + "/review"
+
+	resp, err := http.Get(reviewURL)
+	if err != nil {
+		t.Fatalf("Failed to get review: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected review of an unfinished game to fail; got %v", resp.Status)
+	}
+
+	gameServer := models.GameServers[game["gameId"]]
+	for _, question := range gameServer.Questions {
+		postJSON(t, "/answer", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "questionId": question.ID, "answer": question.Solution()})
+	}
+
+	resp, err = http.Get(reviewURL)
+	if err != nil {
+		t.Fatalf("Failed to get review: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status OK; got %v", resp.Status)
+	}
+
+	var review struct {
+		Questions []struct {
+			ID            string          `json:"id"`
+			CorrectAnswer json.RawMessage `json:"correctAnswer"`
+			Explanation   string          `json:"explanation"`
+			Answer        json.RawMessage `json:"answer"`
+			Correct       bool            `json:"correct"`
+			ClaimedBy     *struct {
+				SessionID string `json:"sessionId"`
+				Name      string `json:"name"`
+			} `json:"claimedBy"`
+		} `json:"questions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&review); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+
+	if len(review.Questions) != 2 {
+		t.Fatalf("Expected 2 questions in the review; got %d", len(review.Questions))
+	}
+	for i, question := range review.Questions {
+		translated := gameServer.Questions[i].Translations["es"].Explanation
+		if string(question.Answer) != string(question.CorrectAnswer) || !question.Correct || translated == "" || question.Explanation != translated {
+			t.Errorf("Unexpected review of question %s: %+v", question.ID, question)
+		}
+		if question.ClaimedBy == nil || question.ClaimedBy.Name != "Review Rita" {
+			t.Errorf("Expected question %s to be claimed by the player", question.ID)
+		}
+	}
+}
+
+// test that practice games bring back missed questions once they are due
+func TestPracticeMode(t *testing.T) {
+	game := startGame(t, map[string]interface{}{"name": "Practice Pat", "questions": 3, "mode": "practice"})
+	gameServer := models.GameServers[game["gameId"]]
+	if gameServer.Multiplayer || gameServer.Settings.Mode != models.GameModePractice || len(gameServer.Questions) != 3 {
+		t.Fatalf("Expected a single player practice game with 3 questions")
+	}
+
+	// Miss the first question and get the others right
+	missed := gameServer.Questions[0]
+	for i, question := range gameServer.Questions {
+		answer := question.Solution()
+		if i == 0 {
+			answer = wrongAnswer(question)
+		}
+		postJSON(t, "/answer", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "questionId": question.ID, "answer": answer})
+	}
+
+	bank, err := controllers.Games.LoadQuestions(100, "")
+	if err != nil {
+		t.Fatalf("Failed to load questions: %v", err)
+	}
+
+	// Right away nothing is due, so unseen questions come first
+	scheduled := models.Repetitions.Schedule(game["playerId"], bank, 1, time.Now())
+	if scheduled[0].ID == missed.ID {
+		t.Errorf("Missed question should not be due yet")
+	}
+
+	// The next day the missed question is due, the correct ones only come back after more days
+	scheduled = models.Repetitions.Schedule(game["playerId"], bank, 1, time.Now().Add(25*time.Hour))
+	if scheduled[0].ID != missed.ID {
+		t.Errorf("Expected missed question %s to be due first; got %s", missed.ID, scheduled[0].ID)
+	}
+
+	status, _ := postJSON(t, "/game/start", map[string]interface{}{"name": "Practice Pat", "questions": 3, "mode": "speedrun"})
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected unknown mode to be rejected; got %v", status)
+	}
+}
+
+// test that every player gets the same daily challenge once per day
+func TestDailyChallenge(t *testing.T) {
+	dana := newPlayerClient(t)
+	first := startGameAs(t, dana, map[string]interface{}{"name": "Daily Dana", "mode": "daily"})
+	second := startGame(t, map[string]interface{}{"name": "Daily Dave", "mode": "daily"})
+
+	firstQuestions := models.GameServers[first["gameId"]].Questions
+	secondQuestions := models.GameServers[second["gameId"]].Questions
+	if len(firstQuestions) == 0 || len(firstQuestions) != len(secondQuestions) {
+		t.Fatalf("Expected both players to get the same number of questions")
+	}
+	for i := range firstQuestions {
+		if firstQuestions[i].ID != secondQuestions[i].ID {
+			t.Fatalf("Expected the same questions in the same order")
+		}
+	}
+
+	// The attempt belongs to the identity in the cookie, an ID or another name in the body does not give a new one
+	status, _ := postJSONAs(t, dana, "/game/start", map[string]interface{}{"name": "Daily Dana", "mode": "daily"})
+	if status != http.StatusConflict {
+		t.Errorf("Expected a second attempt to be rejected; got %v", status)
+	}
+	status, _ = postJSONAs(t, dana, "/game/start", map[string]interface{}{"name": "Dana Again", "playerId": "daily-dana-2", "mode": "daily"})
+	if status != http.StatusConflict {
+		t.Errorf("Expected a second attempt to be rejected; got %v", status)
+	}
+
+	for _, question := range firstQuestions {
+		postJSON(t, "/answer", map[string]interface{}{"gameId": first["gameId"], "sessionId": first["sessionId"], "questionId": question.ID, "answer": question.Solution()})
+	}
+
+	resp, err := http.Get(testServer.URL + "/daily/leaderboard")
+	if err != nil {
+		t.Fatalf("Failed to get daily leaderboard: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var leaderboard struct {
+		Entries []models.DailyLeaderboardEntry `json:"entries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&leaderboard); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+
+	// Only finished attempts are ranked
+	if len(leaderboard.Entries) != 1 || leaderboard.Entries[0].PlayerID != first["playerId"] || leaderboard.Entries[0].Correct != len(firstQuestions) {
+		t.Errorf("Unexpected daily leaderboard: %+v", leaderboard.Entries)
+	}
+
+	// Today's answers are only shown to the player, anyone else could copy them
+	postJSON(t, "/game/end", map[string]interface{}{"gameId": first["gameId"], "sessionId": first["sessionId"]})
+	gamesPath := "/players/" + first["playerId"] + "/games"
+	_, others := getJSONAs(t, newPlayerClient(t), gamesPath)
+	_, own := getJSONAs(t, dana, gamesPath)
+	othersGames, _ := others["games"].([]interface{})
+	ownGames, _ := own["games"].([]interface{})
+	if len(othersGames) != 1 || len(ownGames) != 1 {
+		t.Fatalf("Expected the daily game in the history; got %v and %v", others, own)
+	}
+	if game := othersGames[0].(map[string]interface{}); game["answersHidden"] != true || len(game["answers"].([]interface{})) != 0 {
+		t.Errorf("Expected today's answers to be hidden from other players; got %v", game)
+	}
+	if game := ownGames[0].(map[string]interface{}); game["answersHidden"] == true || len(game["answers"].([]interface{})) != len(firstQuestions) {
+		t.Errorf("Expected the player to see their own answers; got %v", game)
+	}
+}
+
+func getTournament(t *testing.T, tournamentID string) *models.Tournament {
+	resp, err := http.Get(testServer.URL + "/tournaments/" + tournamentID)
+	if err != nil {
+		t.Fatalf("Failed to get tournament: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status OK; got %v", resp.Status)
+	}
+
+	var tournament models.Tournament
+	if err := json.NewDecoder(resp.Body).Decode(&tournament); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	return &tournament
+}
+
+// registerTournamentPlayers registers a player for every name, each with their own identity
+func registerTournamentPlayers(t *testing.T, tournamentID string, organizerToken interface{}, names ...string) map[string]*http.Client {
+	players := make(map[string]*http.Client)
+	for _, name := range names {
+		client := newPlayerClient(t)
+		_, me := getJSONAs(t, client, "/players/me")
+		playerID := me["playerId"].(string)
+		status, _ := postJSON(t, "/tournaments/"+tournamentID+"/players", map[string]interface{}{"organizerToken": organizerToken, "name": name, "playerId": playerID})
+		if status != http.StatusOK {
+			t.Fatalf("Expected status OK; got %v", status)
+		}
+		players[playerID] = client
+	}
+	return players
+}
+
+// tournamentSession returns the session of the player in their current match
+func tournamentSession(t *testing.T, tournamentID string, match *models.TournamentMatch, client *http.Client) string {
+	status, current := getJSONAs(t, client, "/tournaments/"+tournamentID+"/match")
+	if status != http.StatusOK || current["gameId"] != match.GameID {
+		t.Fatalf("Expected the player's match %s; got %v %v", match.GameID, status, current)
+	}
+	return current["sessionId"].(string)
+}
+
+// plays the game of a tournament match, the winner answers every question correctly
+func playTournamentMatch(t *testing.T, tournamentID string, match *models.TournamentMatch, players map[string]*http.Client, winnerID string) {
+	gameServer, err := controllers.GetGameServer(match.GameID)
+	if err != nil {
+		t.Fatalf("Match game not found: %v", err)
+	}
+
+	sessionIDs := make(map[string]string)
+	for _, playerID := range match.PlayerIDs {
+		sessionIDs[playerID] = tournamentSession(t, tournamentID, match, players[playerID])
+	}
+
+	for _, question := range gameServer.Questions {
+		for _, playerID := range match.PlayerIDs {
+			answer := wrongAnswer(question)
+			if playerID == winnerID {
+				answer = question.Solution()
+			}
+			postJSON(t, "/answer", map[string]interface{}{"gameId": match.GameID, "sessionId": sessionIDs[playerID], "questionId": question.ID, "answer": answer})
+		}
+	}
+
+	for _, playerID := range match.PlayerIDs {
+		if status, _ := postJSON(t, "/game/end", map[string]interface{}{"gameId": match.GameID, "sessionId": sessionIDs[playerID]}); status != http.StatusOK {
+			t.Fatalf("Expected status OK; got %v", status)
+		}
+	}
+}
+
+// test that finished match games advance a single-elimination bracket
+func TestTournamentSingleElimination(t *testing.T) {
+	status, created := postJSON(t, "/tournaments", map[string]interface{}{"name": "Quarterly", "format": "single-elimination", "questions": 2})
+	if status != http.StatusOK {
+		t.Fatalf("Expected status OK; got %v", status)
+	}
+	tournamentID := created["tournamentId"].(string)
+
+	players := registerTournamentPlayers(t, tournamentID, created["organizerToken"], "Bracket A", "Bracket B", "Bracket C")
+	seeds := make([]string, 0, len(players))
+	for _, player := range getTournament(t, tournamentID).Players {
+		seeds = append(seeds, player.PlayerID)
+	}
+
+	if status, _ := postJSON(t, "/tournaments/"+tournamentID+"/start", map[string]interface{}{"organizerToken": "wrong"}); status != http.StatusForbidden {
+		t.Errorf("Expected status Forbidden; got %v", status)
+	}
+	if status, _ := postJSON(t, "/tournaments/"+tournamentID+"/start", map[string]interface{}{"organizerToken": created["organizerToken"]}); status != http.StatusOK {
+		t.Fatalf("Expected status OK; got %v", status)
+	}
+
+	// The bracket is public, the sessions of its players are not
+	_, bracket := getJSON(t, "/tournaments/"+tournamentID)
+	for _, match := range bracket["matches"].([]interface{}) {
+		if _, exists := match.(map[string]interface{})["sessionIds"]; exists {
+			t.Fatalf("Expected the bracket to hide the sessions of its players")
+		}
+	}
+	if status, _ := getJSON(t, "/tournaments/"+tournamentID+"/match"); status != http.StatusForbidden {
+		t.Errorf("Expected status Forbidden for a player outside the tournament; got %v", status)
+	}
+
+	// The top seed gets a bye while the other two play
+	tournament := getTournament(t, tournamentID)
+	if len(tournament.Matches) != 2 || !tournament.Matches[0].Finished || tournament.Matches[0].Winner != seeds[0] {
+		t.Fatalf("Unexpected first round: %+v", tournament.Matches)
+	}
+	playTournamentMatch(t, tournamentID, tournament.Matches[1], players, seeds[2])
+
+	tournament = getTournament(t, tournamentID)
+	if tournament.CurrentRound != 2 || len(tournament.Matches) != 3 {
+		t.Fatalf("Expected the final to be paired; got round %d with %d matches", tournament.CurrentRound, len(tournament.Matches))
+	}
+	final := tournament.Matches[2]
+	if final.GameID == "" || final.PlayerIDs[0] != seeds[0] || final.PlayerIDs[1] != seeds[2] {
+		t.Fatalf("Unexpected final: %+v", final)
+	}
+	playTournamentMatch(t, tournamentID, final, players, seeds[2])
+
+	tournament = getTournament(t, tournamentID)
+	if tournament.Status != models.TournamentFinished || tournament.Winner != seeds[2] {
+		t.Errorf("Expected the third seed to win the tournament; got %s won by %s", tournament.Status, tournament.Winner)
+	}
+}
+
+// test that a tournament whose games cannot be created is not started and leaves no game behind
+func TestTournamentStartRollback(t *testing.T) {
+	_, created := postJSON(t, "/tournaments", map[string]interface{}{"name": "Rollback", "format": "swiss", "questions": 2})
+	tournamentID := created["tournamentId"].(string)
+	registerTournamentPlayers(t, tournamentID, created["organizerToken"], "Swiss A", "Swiss B", "Swiss C")
+
+	// The category is gone by the time the tournament starts
+	tournament, _ := models.GetTournament(tournamentID)
+	category := tournament.Category
+	tournament.Category = "missing"
+	status, response := postJSON(t, "/tournaments/"+tournamentID+"/start", map[string]interface{}{"organizerToken": created["organizerToken"]})
+	if status != http.StatusBadRequest || response["code"] != "CATEGORY_NOT_FOUND" {
+		t.Fatalf("Expected the start to fail; got %v %v", status, response["code"])
+	}
+
+	bracket := getTournament(t, tournamentID)
+	if bracket.Status != models.TournamentRegistration || bracket.CurrentRound != 0 || len(bracket.Matches) != 0 || bracket.Rounds != 0 {
+		t.Errorf("Expected the tournament to be open for registration again; got %+v", bracket)
+	}
+	for _, player := range bracket.Players {
+		if player.Points != 0 || player.HadBye {
+			t.Errorf("Expected the bye to be taken back; got %+v", player)
+		}
+	}
+	for _, gameServer := range models.GameServers {
+		if gameServer.Settings.TournamentID == tournamentID {
+			t.Errorf("Expected no game to be left behind; got %s", gameServer.ID)
+		}
+	}
+
+	tournament.Category = category
+	if status, _ := postJSON(t, "/tournaments/"+tournamentID+"/start", map[string]interface{}{"organizerToken": created["organizerToken"]}); status != http.StatusOK {
+		t.Fatalf("Expected the tournament to start once its games can be created; got %v", status)
+	}
+	if bracket := getTournament(t, tournamentID); bracket.CurrentRound != 1 || len(bracket.Matches) != 2 || bracket.Matches[1].GameID == "" {
+		t.Errorf("Unexpected first round: %+v", bracket.Matches)
+	}
+}
+
+// test that a player who does not show up forfeits once the match deadline passes
+func TestTournamentMatchDeadline(t *testing.T) {
+	_, created := postJSON(t, "/tournaments", map[string]interface{}{"name": "Deadline", "questions": 2, "matchSeconds": 1})
+	tournamentID := created["tournamentId"].(string)
+	players := registerTournamentPlayers(t, tournamentID, created["organizerToken"], "Absent Al", "Present Pam")
+	if status, _ := postJSON(t, "/tournaments/"+tournamentID+"/start", map[string]interface{}{"organizerToken": created["organizerToken"]}); status != http.StatusOK {
+		t.Fatalf("Expected status OK; got %v", status)
+	}
+
+	// Only the second seed plays, a wrong answer still beats not showing up
+	match := getTournament(t, tournamentID).Matches[0]
+	present := match.PlayerIDs[1]
+	gameServer, _ := controllers.GetGameServer(match.GameID)
+	question := gameServer.Questions[0]
+	sessionID := tournamentSession(t, tournamentID, match, players[present])
+	postJSON(t, "/answer", map[string]interface{}{"gameId": match.GameID, "sessionId": sessionID, "questionId": question.ID, "answer": wrongAnswer(question)})
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if tournament := getTournament(t, tournamentID); tournament.Status == models.TournamentFinished {
+			if tournament.Winner != present || tournament.Matches[0].Scores[match.PlayerIDs[0]] != -1 {
+				t.Errorf("Expected the absent player to forfeit; got %+v", tournament.Matches[0])
+			}
+			return
+		}
+	}
+	t.Fatalf("Expected the match to be finished at its deadline")
+}
+
+// test that power-ups are limited per session and change what the answer path accepts and scores
+func TestPowerUps(t *testing.T) {
+	// 50/50 only works on single choice questions, every question of this category is one
+	player := newPlayerClient(t)
+	game := startGameAs(t, player, map[string]interface{}{"name": "Lifeline", "questions": 3, "category": "basics", "powerUps": map[string]int{"fiftyFifty": 1, "skip": 1, "doublePoints": 1}})
+	gameServer, err := controllers.GetGameServer(game["gameId"])
+	if err != nil {
+		t.Fatalf("Game not found: %v", err)
+	}
+	usePowerUp := func(powerUp string) (int, map[string]interface{}) {
+		return postJSON(t, "/powerup", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "powerUp": powerUp})
+	}
+
+	first := gameServer.Questions[0]
+	status, used := usePowerUp("fiftyFifty")
+	if status != http.StatusOK {
+		t.Fatalf("Expected status OK; got %v", status)
+	}
+	removed := used["removedOptions"].([]interface{})
+	if len(removed) != 2 {
+		t.Fatalf("Expected two removed options; got %v", removed)
+	}
+	for _, option := range removed {
+		if int(option.(float64)) == first.CorrectIndex {
+			t.Fatalf("50/50 removed the correct option")
+		}
+	}
+	if status, _ := usePowerUp("fiftyFifty"); status != http.StatusConflict {
+		t.Errorf("Expected status Conflict for a used up power-up; got %v", status)
+	}
+
+	status, _ = postJSON(t, "/answer", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "questionId": first.ID, "answer": int(removed[0].(float64))})
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected status Bad Request for a removed option; got %v", status)
+	}
+
+	if status, _ := usePowerUp("doublePoints"); status != http.StatusOK {
+		t.Fatalf("Expected status OK; got %v", status)
+	}
+	_, answered := postJSON(t, "/answer", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "questionId": first.ID, "answer": first.CorrectIndex})
+	if answered["currentScore"] != float64(20) {
+		t.Errorf("Expected double points to score 20; got %v", answered["currentScore"])
+	}
+
+	status, skipped := usePowerUp("skip")
+	if status != http.StatusOK || skipped["nextQuestionIndex"] != float64(2) {
+		t.Fatalf("Expected skip to move on to the third question; got %v %v", status, skipped)
+	}
+	status, _ = postJSON(t, "/answer", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "questionId": gameServer.Questions[1].ID, "answer": gameServer.Questions[1].CorrectIndex})
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected status Bad Request for a skipped question; got %v", status)
+	}
+
+	// The skipped question is neither answered nor wrong on the leaderboard
+	third := gameServer.Questions[2]
+	postJSON(t, "/answer", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "questionId": third.ID, "answer": third.CorrectIndex})
+	_, leaderboard := getJSONAs(t, player, "/leaderboard")
+	entry := leaderboard["player"].(map[string]interface{})
+	if entry["answered"] != float64(2) || entry["accuracy"] != float64(100) {
+		t.Errorf("Expected two answered questions at full accuracy; got %v", entry)
+	}
+}
+
+// test that every question type is graded from its own answer payload
+func TestQuestionTypes(t *testing.T) {
+	questions := []models.Question{
+		{ID: "tf", QuestionText: "Preferred stock converts to common at an IPO", Type: models.QuestionTrueFalse, IsTrue: true},
+		{ID: "multi", QuestionText: "Which of these are dilutive?", Options: []string{"Option grants", "Secondary sale", "SAFE conversion", "Buyback"}, Type: models.QuestionMultiSelect, CorrectIndexes: []int{0, 2}, PartialCredit: true},
+		{ID: "numeric", QuestionText: "Post-money ownership % of a $2M investment at $8M pre-money", Type: models.QuestionNumeric, CorrectNumber: 20, Tolerance: 0.5},
+		{ID: "order", QuestionText: "Order the rounds", Options: []string{"Series A", "Seed", "Series B"}, Type: models.QuestionOrdering, CorrectOrder: []int{1, 0, 2}},
+	}
+	for _, question := range questions {
+		if err := question.Validate(); err != nil {
+			t.Fatalf("Expected a valid question: %v", err)
+		}
+	}
+
+	gameServer := controllers.Games.NewGameServer(questions, models.NewSessionStore(models.NamePolicySuffix), false, models.GameSettings{})
+	sessionID, err := gameServer.Sessions.CreateSession("Grader", "question-types")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	for _, question := range controllers.RemoveAnswers(questions) {
+		if question.CorrectNumber != 0 || question.CorrectIndexes != nil || question.CorrectOrder != nil || question.IsTrue {
+			t.Errorf("Expected the answer of %s to be removed", question.ID)
+		}
+	}
+
+	tests := []struct {
+		questionID string
+		answer     interface{}
+		status     int
+		credit     float64
+	}{
+		{"tf", "yes", http.StatusBadRequest, 0},
+		{"tf", true, http.StatusOK, 1},
+		{"multi", []int{0, 1, 2}, http.StatusOK, 0.5},
+		{"numeric", 20.4, http.StatusOK, 1},
+		{"order", []int{0, 1, 2}, http.StatusOK, 0},
+	}
+	for _, test := range tests {
+		status, answered := postJSON(t, "/answer", map[string]interface{}{"gameId": gameServer.ID, "sessionId": sessionID, "questionId": test.questionID, "answer": test.answer})
+		if status != test.status {
+			t.Fatalf("Expected status %v for %s; got %v", test.status, test.questionID, status)
+		}
+		if status == http.StatusOK && answered["credit"] != test.credit {
+			t.Errorf("Expected credit %v for %s; got %v", test.credit, test.questionID, answered["credit"])
+		}
+	}
+
+	session, _ := gameServer.Sessions.GetSession(sessionID)
+	if session.Score != 25 || session.Correct != 2 {
+		t.Errorf("Expected a score of 25 with 2 correct answers; got %d with %d", session.Score, session.Correct)
+	}
+}
+
+// test that generated scenario questions can be played and built again from their ID
+func TestGeneratedQuestions(t *testing.T) {
+	game := startGame(t, map[string]interface{}{"name": "Modeler", "questions": 8, "category": generator.Category})
+	gameServer, err := controllers.GetGameServer(game["gameId"])
+	if err != nil {
+		t.Fatalf("Game not found: %v", err)
+	}
+	if len(gameServer.Questions) != 8 {
+		t.Fatalf("Expected 8 generated questions; got %d", len(gameServer.Questions))
+	}
+
+	for _, question := range gameServer.Questions {
+		if err := question.Validate(); err != nil {
+			t.Errorf("Generated an invalid question: %v", err)
+		}
+		if len(question.Options) != 4 {
+			t.Errorf("Expected 4 options for %s; got %v", question.ID, question.Options)
+		}
+
+		regenerated, err := generator.Regenerate(question.ID)
+		if err != nil {
+			t.Fatalf("Failed to regenerate %s: %v", question.ID, err)
+		}
+		if regenerated.QuestionText != question.QuestionText || regenerated.Options[regenerated.CorrectIndex] != question.Options[question.CorrectIndex] {
+			t.Errorf("Expected %s to be built again the same way", question.ID)
+		}
+	}
+}
+
+// test that the ownership scenarios never ask about owning more than the whole company
+func TestGeneratedOwnershipBelowWhole(t *testing.T) {
+	for _, kind := range []generator.Scenario{generator.ScenarioSAFE, generator.ScenarioPricedRound} {
+		for seed := int64(0); seed < 2000; seed++ {
+			question, err := generator.Generate(kind, seed)
+			if err != nil {
+				t.Fatalf("Failed to generate %s: %v", kind, err)
+			}
+			for _, option := range question.Options {
+				percent, err := strconv.ParseFloat(strings.TrimSuffix(option, "%"), 64)
+				if err != nil || percent <= 0 || percent >= 100 {
+					t.Fatalf("Expected an ownership between 0%% and 100%% for %s; got %s", question.ID, option)
+				}
+			}
+		}
+	}
+}
+
+// test that questions follow the locale of the session and errors follow the locale of the request
+func TestLocalization(t *testing.T) {
+	questions := []models.Question{{
+		ID:           "localized",
+		QuestionText: "What is a cap table?",
+		Options:      []string{"A ledger of ownership", "A spreadsheet of expenses"},
+		Translations: map[string]models.QuestionTranslation{
+			"es": {QuestionText: "¿Qué es una tabla de capitalización?", Options: []string{"Un registro de la propiedad", "Una hoja de gastos"}},
+			// Options that do not cover every option are ignored
+			"fr": {QuestionText: "Qu'est-ce qu'une table de capitalisation ?", Options: []string{"Un registre"}},
+		},
+	}}
+	gameServer := controllers.Games.NewGameServer(questions, models.NewSessionStore(models.NamePolicySuffix), true, models.GameSettings{})
+
+	tests := []struct {
+		locale       string
+		questionText string
+		option       string
+	}{
+		{"es-MX", "¿Qué es una tabla de capitalización?", "Un registro de la propiedad"},
+		{"fr", "Qu'est-ce qu'une table de capitalisation ?", "A ledger of ownership"},
+		{"de", "What is a cap table?", "A ledger of ownership"},
+	}
+	for _, test := range tests {
+		_, joined := postJSON(t, "/game/join", map[string]interface{}{"gameId": gameServer.ID, "name": "Locale " + test.locale, "locale": test.locale})
+
+		resp, err := http.Get(testServer.URL + "/game/" + gameServer.ID + "/" + joined["sessionId"].(string))
+		if err != nil {
+			t.Fatalf("Failed to get game: %v", err)
+		}
+		var game struct {
+			Questions []models.Question `json:"questions"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&game)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("Failed to decode JSON response: %v", err)
+		}
+
+		if game.Questions[0].QuestionText != test.questionText || game.Questions[0].Options[0] != test.option {
+			t.Errorf("Unexpected question for %s: %+v", test.locale, game.Questions[0])
+		}
+	}
+
+	single := startGame(t, map[string]interface{}{"name": "Solo", "questions": 3})
+	body, _ := json.Marshal(map[string]interface{}{"gameId": single["gameId"], "name": "Visitor"})
+	req, _ := http.NewRequest(http.MethodPost, testServer.URL+"/game/join", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "fr-CA,fr;q=0.9,en;q=0.8")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to join game: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var response map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	if response["error"] != "Impossible de rejoindre une partie solo" {
+		t.Errorf("Expected the error in French; got %q", response["error"])
+	}
+
+	// Errors returned by the models are in the catalog too
+	req, _ = http.NewRequest(http.MethodGet, testServer.URL+"/matchmaking/missing", nil)
+	req.Header.Set("Accept-Language", "es")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to get ticket: %v", err)
+	}
+	defer resp.Body.Close()
+
+	response = nil
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	if response["error"] != "Ticket de emparejamiento no encontrado" {
+		t.Errorf("Expected the error in Spanish; got %q", response["error"])
+	}
+}
+
+func getJSON(t *testing.T, path string) (int, map[string]interface{}) {
+	return getJSONAs(t, http.DefaultClient, path)
+}
+
+func getJSONAs(t *testing.T, client *http.Client, path string) (int, map[string]interface{}) {
+	resp, err := client.Get(testServer.URL + path)
+	if err != nil {
+		t.Fatalf("Failed to get %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	return resp.StatusCode, response
+}
+
+// test that failed requests are rendered with a stable error code and a message
+func TestErrorCodes(t *testing.T) {
+	single := startGame(t, map[string]interface{}{"name": "Coder", "questions": 3})
+	multi := startGame(t, map[string]interface{}{"name": "Host", "multiplayer": true, "questions": 3, "capacity": 1})
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		payload map[string]interface{}
+		status  int
+		code    string
+	}{
+		{"missing game", http.MethodGet, "/game/missing/session", nil, http.StatusNotFound, "GAME_NOT_FOUND"},
+		{"missing session", http.MethodGet, "/game/" + single["gameId"] + "/missing", nil, http.StatusNotFound, "SESSION_NOT_FOUND"},
+		{"invalid body", http.MethodPost, "/answer", map[string]interface{}{"gameId": 42}, http.StatusBadRequest, "INVALID_REQUEST_BODY"},
+		{"missing game on answer", http.MethodPost, "/answer", map[string]interface{}{"gameId": "missing", "sessionId": "missing", "questionId": "missing", "answer": 0}, http.StatusNotFound, "GAME_NOT_FOUND"},
+		{"missing question", http.MethodPost, "/answer", map[string]interface{}{"gameId": single["gameId"], "sessionId": single["sessionId"], "questionId": "missing", "answer": 0}, http.StatusNotFound, "QUESTION_NOT_FOUND"},
+		{"single player game", http.MethodPost, "/game/join", map[string]interface{}{"gameId": single["gameId"], "name": "Visitor"}, http.StatusBadRequest, "SINGLE_PLAYER_GAME"},
+		{"full game", http.MethodPost, "/game/join", map[string]interface{}{"gameId": multi["gameId"], "name": "Visitor"}, http.StatusBadRequest, "GAME_FULL"},
+		{"invalid name", http.MethodPost, "/game/start", map[string]interface{}{"name": "", "questions": 3}, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"unknown category", http.MethodPost, "/game/start", map[string]interface{}{"name": "Lost", "questions": 3, "category": "missing"}, http.StatusBadRequest, "CATEGORY_NOT_FOUND"},
+		{"not the owner", http.MethodPost, "/game/kick", map[string]interface{}{"gameId": multi["gameId"], "sessionId": "someone", "targetSessionId": "other"}, http.StatusForbidden, "NOT_GAME_OWNER"},
+		{"invalid window", http.MethodGet, "/leaderboard?window=yearly", nil, http.StatusBadRequest, "INVALID_PARAMETER"},
+		{"invalid limit", http.MethodGet, "/games?limit=-1", nil, http.StatusBadRequest, "INVALID_PARAMETER"},
+		{"missing player", http.MethodGet, "/players/missing", nil, http.StatusNotFound, "PLAYER_NOT_FOUND"},
+		{"missing ticket", http.MethodGet, "/matchmaking/missing", nil, http.StatusNotFound, "TICKET_NOT_FOUND"},
+		{"missing tournament", http.MethodGet, "/tournaments/missing", nil, http.StatusNotFound, "TOURNAMENT_NOT_FOUND"},
+	}
+	for _, test := range tests {
+		var status int
+		var response map[string]interface{}
+		if test.method == http.MethodGet {
+			status, response = getJSON(t, test.path)
+		} else {
+			status, response = postJSON(t, test.path, test.payload)
+		}
+
+		if status != test.status || response["code"] != test.code {
+			t.Errorf("%s: expected %v %s; got %v %v", test.name, test.status, test.code, status, response["code"])
+		}
+		if message, ok := response["error"].(string); !ok || message == "" {
+			t.Errorf("%s: expected an error message; got %v", test.name, response["error"])
+		}
+	}
+
+	// Game finished is reported once the game is over
+	gameServer, _ := controllers.GetGameServer(multi["gameId"])
+	gameServer.MarkFinished()
+	gameServer.Settings.Capacity = 0
+	status, response := postJSON(t, "/game/join", map[string]interface{}{"gameId": multi["gameId"], "name": "Late"})
+	if status != http.StatusBadRequest || response["code"] != "GAME_ALREADY_FINISHED" {
+		t.Errorf("Expected GAME_ALREADY_FINISHED; got %v %v", status, response["code"])
+	}
+}
+
+// test that invalid request bodies are rejected with the reason for every field
+func TestRequestValidation(t *testing.T) {
+	game := startGame(t, map[string]interface{}{"name": "Validator", "multiplayer": true, "questions": 3})
+	question := models.GameServers[game["gameId"]].Questions[0]
+
+	tests := []struct {
+		name    string
+		path    string
+		payload map[string]interface{}
+		fields  []string
+	}{
+		{"empty name", "/game/start", map[string]interface{}{"name": "", "questions": 3}, []string{"name"}},
+		{"long name", "/game/start", map[string]interface{}{"name": strings.Repeat("a", 100), "questions": 3}, []string{"name"}},
+		{"no questions", "/game/start", map[string]interface{}{"name": "Zero", "questions": 0}, []string{"questions"}},
+		{"negative questions", "/game/start", map[string]interface{}{"name": "Negative", "questions": -1}, []string{"questions"}},
+		{"too many questions", "/game/start", map[string]interface{}{"name": "Greedy", "questions": 100000}, []string{"questions"}},
+		{"several fields", "/game/start", map[string]interface{}{"name": "", "questions": 3, "capacity": -1, "mode": "speedrun", "namePolicy": "any"}, []string{"name", "capacity", "mode", "namePolicy"}},
+		{"unknown power-up", "/game/start", map[string]interface{}{"name": "Powerful", "questions": 3, "powerUps": map[string]int{"teleport": 1}}, []string{"powerUps[teleport]"}},
+		{"negative power-up uses", "/game/start", map[string]interface{}{"name": "Powerful", "questions": 3, "powerUps": map[string]int{"skip": -1}}, []string{"powerUps[skip]"}},
+		{"join without game", "/game/join", map[string]interface{}{"name": "Visitor"}, []string{"gameId"}},
+		{"join without name", "/game/join", map[string]interface{}{"gameId": game["gameId"]}, []string{"name"}},
+		{"answer without answer", "/answer", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "questionId": question.ID}, []string{"answer"}},
+		{"end without session", "/game/end", map[string]interface{}{"gameId": game["gameId"]}, []string{"sessionId"}},
+		{"kick without target", "/game/kick", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"]}, []string{"targetSessionId"}},
+		{"unknown power-up use", "/powerup", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "powerUp": "teleport"}, []string{"powerUp"}},
+		{"enqueue too many questions", "/matchmaking/enqueue", map[string]interface{}{"name": "Queued", "questions": 100000}, []string{"questions"}},
+		{"cancel without ticket", "/matchmaking/cancel", map[string]interface{}{}, []string{"ticketId"}},
+		{"tournament without name", "/tournaments", map[string]interface{}{"format": "round-robin", "rounds": -1}, []string{"name", "format", "rounds"}},
+	}
+	for _, test := range tests {
+		status, response := postJSON(t, test.path, test.payload)
+		if status != http.StatusBadRequest || response["code"] != "VALIDATION_FAILED" {
+			t.Errorf("%s: expected 400 VALIDATION_FAILED; got %v %v", test.name, status, response["code"])
+			continue
+		}
+
+		details, _ := response["details"].(map[string]interface{})
+		fields, _ := details["fields"].(map[string]interface{})
+		if len(fields) != len(test.fields) {
+			t.Errorf("%s: expected errors for %v; got %v", test.name, test.fields, fields)
+		}
+		for _, field := range test.fields {
+			if message, ok := fields[field].(string); !ok || message == "" {
+				t.Errorf("%s: expected an error for %s; got %v", test.name, field, fields)
+			}
+		}
+	}
+
+	// The reasons are in the language of the request
+	body, _ := json.Marshal(map[string]interface{}{"name": "Greedy", "questions": 100000})
+	request, _ := http.NewRequest(http.MethodPost, testServer.URL+"/game/start", bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept-Language", "es")
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	defer resp.Body.Close()
+	var localized struct {
+		Details struct {
+			Fields map[string]string `json:"fields"`
+		} `json:"details"`
+	}
+	json.NewDecoder(resp.Body).Decode(&localized)
+	if !strings.HasPrefix(localized.Details.Fields["questions"], "debe estar entre 1 y ") {
+		t.Errorf("Expected the reason in Spanish; got %v", localized.Details.Fields)
+	}
+
+	// The daily challenge does not need a number of questions
+	daily := startGame(t, map[string]interface{}{"name": "Daily Val", "mode": "daily"})
+	if daily["gameId"] == "" {
+		t.Errorf("Expected the daily challenge to start without a number of questions")
+	}
+
+	// Option indexes must point to an option of the question
+	for _, candidate := range models.GameServers[game["gameId"]].Questions {
+		if candidate.Kind() == models.QuestionSingleChoice {
+			question = candidate
+			break
+		}
+	}
+	status, response := postJSON(t, "/answer", map[string]interface{}{"gameId": game["gameId"], "sessionId": game["sessionId"], "questionId": question.ID, "answer": len(question.Options)})
+	if status != http.StatusBadRequest || response["code"] != "INVALID_ANSWER" {
+		t.Errorf("Expected an out of range option to be rejected; got %v %v", status, response["code"])
+	}
+}
+
+// openAPISpec is the part of the OpenAPI specification the contract tests check responses against
+type openAPISpec struct {
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components struct {
+		Schemas   map[string]map[string]interface{} `json:"schemas"`
+		Responses map[string]openAPIResponse        `json:"responses"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	Responses map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIResponse struct {
+	Ref     string `json:"$ref"`
+	Content map[string]struct {
+		Schema map[string]interface{} `json:"schema"`
+	} `json:"content"`
+}
+
+func getOpenAPISpec(t *testing.T) *openAPISpec {
+	resp, err := http.Get(testServer.URL + routes.APIPrefix + "/openapi.json")
+	if err != nil {
+		t.Fatalf("Failed to get the OpenAPI specification: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var spec openAPISpec
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		t.Fatalf("Failed to decode the OpenAPI specification: %v", err)
+	}
+	return &spec
+}
+
+// openAPIPath returns the path of the specification a request path matches, paths without parameters win
+func (spec *openAPISpec) openAPIPath(path string) string {
+	segments := strings.Split(strings.Split(path, "?")[0], "/")
+	match, matchParameters := "", len(segments)+1
+	for template := range spec.Paths {
+		templateSegments := strings.Split(template, "/")
+		if len(templateSegments) != len(segments) {
+			continue
+		}
+
+		parameters := 0
+		matches := true
+		for i, segment := range templateSegments {
+			if strings.HasPrefix(segment, "{") {
+				parameters++
+			} else if segment != segments[i] {
+				matches = false
+				break
+			}
+		}
+		if matches && parameters < matchParameters {
+			match, matchParameters = template, parameters
+		}
+	}
+	return match
+}
+
+// checkSchema returns where the value does not follow the schema
+func (spec *openAPISpec) checkSchema(schema map[string]interface{}, value interface{}, at string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return spec.checkSchema(spec.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")], value, at)
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		for _, option := range anyOf {
+			if len(spec.checkSchema(option.(map[string]interface{}), value, at)) == 0 {
+				return nil
+			}
+		}
+		return []string{at + " matches none of the allowed schemas"}
+	}
+
+	schemaType, _ := schema["type"].(string)
+	if value == nil {
+		if schemaType == "" || schema["nullable"] == true {
+			return nil
+		}
+		return []string{at + " is null"}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		allowed := false
+		for _, option := range enum {
+			allowed = allowed || option == value
+		}
+		if !allowed {
+			return []string{fmt.Sprintf("%s is %v, not one of %v", at, value, enum)}
+		}
+	}
+
+	var problems []string
+	switch schemaType {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{at + " is not an object"}
+		}
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, exists := object[name.(string)]; !exists {
+					problems = append(problems, fmt.Sprintf("%s.%s is missing", at, name))
+				}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range object {
+			if propertySchema, declared := properties[name]; declared {
+				problems = append(problems, spec.checkSchema(propertySchema.(map[string]interface{}), property, at+"."+name)...)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					problems = append(problems, fmt.Sprintf("%s.%s is not in the specification", at, name))
+				}
+			case map[string]interface{}:
+				problems = append(problems, spec.checkSchema(additional, property, at+"."+name)...)
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return []string{at + " is not an array"}
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range array {
+				problems = append(problems, spec.checkSchema(items, item, fmt.Sprintf("%s[%d]", at, i))...)
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return []string{at + " is not a string"}
+		}
+		if minLength, ok := schema["minLength"].(float64); ok && float64(len([]rune(text))) < minLength {
+			problems = append(problems, at+" is too short")
+		}
+		if maxLength, ok := schema["maxLength"].(float64); ok && float64(len([]rune(text))) > maxLength {
+			problems = append(problems, at+" is too long")
+		}
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok || (schemaType == "integer" && number != float64(int64(number))) {
+			return []string{fmt.Sprintf("%s is not an %s", at, schemaType)}
+		}
+		if minimum, ok := schema["minimum"].(float64); ok && number < minimum {
+			problems = append(problems, fmt.Sprintf("%s is below %v", at, minimum))
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && number > maximum {
+			problems = append(problems, fmt.Sprintf("%s is above %v", at, maximum))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{at + " is not a boolean"}
+		}
+	}
+	return problems
+}
+
+// contractRequest sends a request and fails the test if the response is not the one the specification documents
+func contractRequest(t *testing.T, spec *openAPISpec, method string, path string, payload map[string]interface{}) (int, map[string]interface{}) {
+	t.Helper()
+
+	var body *bytes.Reader
+	if payload != nil {
+		encoded, _ := json.Marshal(payload)
+		body = bytes.NewReader(encoded)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+	req, _ := http.NewRequest(method, testServer.URL+routes.APIPrefix+path, body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send %s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var response interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("%s %s: failed to decode JSON response: %v", method, path, err)
+	}
+	object, _ := response.(map[string]interface{})
+
+	operation, documented := spec.Paths[spec.openAPIPath(path)][strings.ToLower(method)]
+	if !documented {
+		t.Errorf("%s %s is not in the specification", method, path)
+		return resp.StatusCode, object
+	}
+	documentedResponse, documented := operation.Responses[fmt.Sprint(resp.StatusCode)]
+	if !documented {
+		t.Errorf("%s %s: status %d is not in the specification", method, path, resp.StatusCode)
+		return resp.StatusCode, object
+	}
+	if documentedResponse.Ref != "" {
+		documentedResponse = spec.Components.Responses[strings.TrimPrefix(documentedResponse.Ref, "#/components/responses/")]
+	}
+
+	for _, problem := range spec.checkSchema(documentedResponse.Content["application/json"].Schema, response, "response") {
+		t.Errorf("%s %s %d: %s", method, path, resp.StatusCode, problem)
+	}
+	return resp.StatusCode, object
+}
+
+// test that every game and answer route is in the OpenAPI specification
+func TestOpenAPIRoutes(t *testing.T) {
+	spec := getOpenAPISpec(t)
+
+	router := gin.New()
+	routes.GameRoutes(router)
+	routes.AnswerRoutes(router)
+	parameter := regexp.MustCompile(`:(\w+)`)
+	for _, route := range router.Routes() {
+		path := parameter.ReplaceAllString(route.Path, "{$1}")
+		if _, documented := spec.Paths[path][strings.ToLower(route.Method)]; !documented {
+			t.Errorf("%s %s is not in the specification", route.Method, path)
+		}
+	}
+
+	// The specification must not document routes that do not exist, its paths are relative to the API prefix
+	registered := make(map[string]bool)
+	for _, route := range testRouter.Routes() {
+		if strings.HasPrefix(route.Path, routes.APIPrefix+"/") {
+			registered[strings.ToLower(route.Method)+" "+parameter.ReplaceAllString(strings.TrimPrefix(route.Path, routes.APIPrefix), "{$1}")] = true
+		}
+	}
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if !registered[method+" "+path] {
+				t.Errorf("%s %s is in the specification but not registered", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+// test that real responses of the game and answer routes match the OpenAPI specification
+func TestOpenAPIContract(t *testing.T) {
+	spec := getOpenAPISpec(t)
+
+	_, single := contractRequest(t, spec, http.MethodPost, "/game/start", map[string]interface{}{"name": "Contract", "questions": 3})
+	_, multi := contractRequest(t, spec, http.MethodPost, "/game/start", map[string]interface{}{"name": "Contract Host", "multiplayer": true, "public": true, "questions": 3})
+	if status, _ := contractRequest(t, spec, http.MethodPost, "/game/start", map[string]interface{}{"name": "", "questions": 3}); status != http.StatusBadRequest {
+		t.Errorf("Expected an invalid name to be rejected; got %v", status)
+	}
+	singleGame, singleSession := single["gameId"].(string), single["sessionId"].(string)
+	multiGame, ownerSession := multi["gameId"].(string), multi["sessionId"].(string)
+
+	_, guest := contractRequest(t, spec, http.MethodPost, "/game/join", map[string]interface{}{"gameId": multiGame, "name": "Contract Guest"})
+	contractRequest(t, spec, http.MethodPost, "/game/join", map[string]interface{}{"gameId": singleGame, "name": "Contract Guest"})
+	contractRequest(t, spec, http.MethodPost, "/game/join", map[string]interface{}{"gameId": "missing", "name": "Contract Guest"})
+
+	contractRequest(t, spec, http.MethodGet, "/game/"+multiGame+"/"+ownerSession, nil)
+	contractRequest(t, spec, http.MethodGet, "/game/"+singleGame+"/"+singleSession, nil)
+	contractRequest(t, spec, http.MethodGet, "/game/missing/"+singleSession, nil)
+	contractRequest(t, spec, http.MethodGet, "/game/missing/ws", nil)
+	contractRequest(t, spec, http.MethodGet, "/game/missing/events", nil)
+	contractRequest(t, spec, http.MethodGet, "/games", nil)
+	contractRequest(t, spec, http.MethodGet, "/games?limit=-1", nil)
+
+	// Review is not available until the session has answered every question
+	if status, _ := contractRequest(t, spec, http.MethodGet, "/game/"+singleGame+"/"+singleSession+"/review", nil); status != http.StatusBadRequest {
+		t.Errorf("Expected review to be unavailable; got %v", status)
+	}
+
+	questions := models.GameServers[singleGame].Questions
+	powerUp := func(powerUp string) int {
+		status, _ := contractRequest(t, spec, http.MethodPost, "/powerup", map[string]interface{}{"gameId": singleGame, "sessionId": singleSession, "powerUp": powerUp})
+		return status
+	}
+	powerUp("fiftyFifty")
+	powerUp("skip")
+	powerUp("doublePoints")
+	if status := powerUp("doublePoints"); status != http.StatusConflict {
+		t.Errorf("Expected a used up power-up to be rejected; got %v", status)
+	}
+	contractRequest(t, spec, http.MethodPost, "/powerup", map[string]interface{}{"gameId": "missing", "sessionId": singleSession, "powerUp": "skip"})
+
+	answer := func(question models.Question, answer interface{}) int {
+		status, _ := contractRequest(t, spec, http.MethodPost, "/answer", map[string]interface{}{"gameId": singleGame, "sessionId": singleSession, "questionId": question.ID, "answer": answer})
+		return status
+	}
+	if status := answer(questions[1], "not an answer"); status != http.StatusBadRequest {
+		t.Errorf("Expected an answer of the wrong shape to be rejected; got %v", status)
+	}
+	answer(questions[1], questions[1].Solution())
+	answer(questions[2], wrongAnswer(questions[2]))
+	contractRequest(t, spec, http.MethodPost, "/answer", map[string]interface{}{"gameId": "missing", "sessionId": singleSession, "questionId": questions[0].ID, "answer": 0})
+
+	if status, _ := contractRequest(t, spec, http.MethodGet, "/game/"+singleGame+"/"+singleSession+"/review", nil); status != http.StatusOK {
+		t.Errorf("Expected the review of a finished session; got %v", status)
+	}
+	contractRequest(t, spec, http.MethodPost, "/game/end", map[string]interface{}{"gameId": singleGame, "sessionId": singleSession})
+
+	guestSession := guest["sessionId"].(string)
+	contractRequest(t, spec, http.MethodPost, "/game/ban", map[string]interface{}{"gameId": multiGame, "sessionId": guestSession, "targetSessionId": ownerSession})
+	contractRequest(t, spec, http.MethodPost, "/game/kick", map[string]interface{}{"gameId": multiGame, "sessionId": ownerSession, "targetSessionId": ownerSession})
+	contractRequest(t, spec, http.MethodPost, "/game/kick", map[string]interface{}{"gameId": multiGame, "sessionId": ownerSession, "targetSessionId": guestSession})
+	contractRequest(t, spec, http.MethodPost, "/game/end", map[string]interface{}{"gameId": multiGame, "sessionId": ownerSession})
+}
+
+// test that the API is served under /api/v1 and the legacy paths still work but are deprecated
+func TestVersionedRoutes(t *testing.T) {
+	game := startGame(t, map[string]interface{}{"name": "Versioned", "questions": 3})
+
+	tests := []struct {
+		path       string
+		deprecated bool
+	}{
+		{routes.APIPrefix + "/game/" + game["gameId"] + "/" + game["sessionId"], false},
+		{"/game/" + game["gameId"] + "/" + game["sessionId"], true},
+		{routes.APIPrefix + "/games?limit=5", false},
+		{"/games?limit=5", true},
+	}
+	for _, test := range tests {
+		resp, err := http.Get(testServer.URL + test.path)
+		if err != nil {
+			t.Fatalf("Failed to get %s: %v", test.path, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: expected status OK; got %v", test.path, resp.StatusCode)
+		}
+		if deprecated := resp.Header.Get("Deprecation") == "true"; deprecated != test.deprecated {
+			t.Errorf("%s: expected deprecated %v; got %v", test.path, test.deprecated, deprecated)
+		}
+		if test.deprecated && resp.Header.Get("Link") != "<"+routes.APIPrefix+test.path+">; rel=\"successor-version\"" {
+			t.Errorf("%s: expected a link to the successor; got %q", test.path, resp.Header.Get("Link"))
+		}
+	}
+
+	// Requests to the new paths are served by the same handlers
+	status, response := postJSON(t, routes.APIPrefix+"/game/join", map[string]interface{}{"gameId": game["gameId"], "name": "Visitor"})
+	if status != http.StatusBadRequest || response["code"] != "SINGLE_PLAYER_GAME" {
+		t.Errorf("Expected SINGLE_PLAYER_GAME; got %v %v", status, response["code"])
+	}
+}
+
+// readServerSentEvent reads the next event of a Server-Sent Events stream, skipping comments
+func readServerSentEvent(t *testing.T, events *bufio.Reader) (string, models.Message) {
+	t.Helper()
+
+	var name string
+	var message models.Message
+	for {
+		line, err := events.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && name != "":
+			return name, message
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimPrefix(line, "event:")
+		case strings.HasPrefix(line, "data:"):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &message); err != nil {
+				t.Fatalf("Failed to decode event data: %v", err)
+			}
+		}
+	}
+}
+
+// test that the event stream sends the same messages as the game websocket
+func TestGameEventStream(t *testing.T) {
+	game := startGame(t, map[string]interface{}{"name": "Streamer", "multiplayer": true, "questions": 3})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, testServer.URL+routes.APIPrefix+"/game/"+game["gameId"]+"/events?sessionId="+game["sessionId"], nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open the event stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("Expected an event stream; got %v %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	events := bufio.NewReader(resp.Body)
+	for _, expected := range []string{"allPlayers", "scoreUpdate"} {
+		name, message := readServerSentEvent(t, events)
+		if name != expected || message.Type != expected {
+			t.Fatalf("Expected %s; got event %s with message %+v", expected, name, message)
+		}
+	}
+
+	status, joined := postJSON(t, "/game/join", map[string]interface{}{"gameId": game["gameId"], "name": "Viewer"})
+	if status != http.StatusOK {
+		t.Fatalf("Failed to join game: %v", status)
+	}
+	name, message := readServerSentEvent(t, events)
+	var content map[string]string
+	json.Unmarshal([]byte(message.Content), &content)
+	if name != "playerJoined" || content["sessionId"] != joined["sessionId"] {
+		t.Errorf("Expected playerJoined for %v; got %s %+v", joined["sessionId"], name, message)
+	}
+
+	// Subscribers are removed from the hub when the stream closes
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for len(models.GetOrCreateHub().GetAllClients(game["gameId"])) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the subscriber to be removed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGameRPC(t *testing.T) {
+	client := captriviav1connect.NewGameServiceClient(http.DefaultClient, testServer.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	started, err := client.StartGame(ctx, connect.NewRequest(&captriviav1.StartGameRequest{Name: "Remote Rita", Questions: 2}))
+	if err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	game, err := client.GetGame(ctx, connect.NewRequest(&captriviav1.GetGameRequest{GameId: started.Msg.GameId, SessionId: started.Msg.SessionId}))
+	if err != nil {
+		t.Fatalf("Failed to get game: %v", err)
+	}
+	if len(game.Msg.Questions) != 2 || !game.Msg.Owner {
+		t.Fatalf("Expected 2 questions for the owner; got %+v", game.Msg)
+	}
+
+	// Answers are graded by the same service as the REST API
+	gameServer := models.GameServers[started.Msg.GameId]
+	for i, question := range gameServer.Questions {
+		answer := rpcAnswer(question)
+		result, err := client.SubmitAnswer(ctx, connect.NewRequest(&captriviav1.SubmitAnswerRequest{GameId: started.Msg.GameId, SessionId: started.Msg.SessionId, QuestionId: question.ID, Answer: answer}))
+		if err != nil {
+			t.Fatalf("Failed to submit answer: %v", err)
+		}
+		if !result.Msg.Correct || result.Msg.NextQuestionIndex != int32(i+1) {
+			t.Errorf("Expected a correct answer; got %+v", result.Msg)
+		}
+	}
+
+	ended, err := client.EndGame(ctx, connect.NewRequest(&captriviav1.EndGameRequest{GameId: started.Msg.GameId, SessionId: started.Msg.SessionId}))
+	if err != nil {
+		t.Fatalf("Failed to end game: %v", err)
+	}
+	if ended.Msg.FinalScore != 20 || !ended.Msg.Finished {
+		t.Errorf("Expected a finished game with a score of 20; got %+v", ended.Msg)
+	}
+
+	// Errors carry the connect code of the HTTP status and the API error code
+	_, err = client.GetGame(ctx, connect.NewRequest(&captriviav1.GetGameRequest{GameId: "missing", SessionId: "missing"}))
+	var rpcError *connect.Error
+	if !errors.As(err, &rpcError) || rpcError.Code() != connect.CodeNotFound || rpcError.Meta().Get("Error-Code") != string(controllers.CodeGameNotFound) {
+		t.Errorf("Expected a not found error; got %v", err)
+	}
+	_, err = client.StartGame(ctx, connect.NewRequest(&captriviav1.StartGameRequest{Name: ""}))
+	if !errors.As(err, &rpcError) || rpcError.Code() != connect.CodeInvalidArgument || !strings.Contains(rpcError.Meta().Get("Error-Details"), "name") {
+		t.Errorf("Expected a validation error; got %v", err)
+	}
+}
+
+// rpcAnswer returns the correct answer to the question as an RPC answer
+func rpcAnswer(question models.Question) *captriviav1.Answer {
+	indexes := func(values []int) *captriviav1.OptionIndexes {
+		converted := &captriviav1.OptionIndexes{}
+		for _, value := range values {
+			converted.Indexes = append(converted.Indexes, int32(value))
+		}
+		return converted
+	}
+
+	switch question.Kind() {
+	case models.QuestionTrueFalse:
+		return &captriviav1.Answer{Value: &captriviav1.Answer_IsTrue{IsTrue: question.IsTrue}}
+	case models.QuestionMultiSelect:
+		return &captriviav1.Answer{Value: &captriviav1.Answer_Options{Options: indexes(question.CorrectIndexes)}}
+	case models.QuestionNumeric:
+		return &captriviav1.Answer{Value: &captriviav1.Answer_Number{Number: question.CorrectNumber}}
+	case models.QuestionOrdering:
+		return &captriviav1.Answer{Value: &captriviav1.Answer_Order{Order: indexes(question.CorrectOrder)}}
+	default:
+		return &captriviav1.Answer{Value: &captriviav1.Answer_Option{Option: int32(question.CorrectIndex)}}
+	}
+}
+
+func TestGameRPCEventStream(t *testing.T) {
+	game := startGame(t, map[string]interface{}{"name": "Streamer", "multiplayer": true, "questions": 3})
+	client := captriviav1connect.NewGameServiceClient(http.DefaultClient, testServer.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamEvents(ctx, connect.NewRequest(&captriviav1.StreamEventsRequest{GameId: game["gameId"], SessionId: game["sessionId"]}))
+	if err != nil {
+		t.Fatalf("Failed to open the event stream: %v", err)
+	}
+	defer stream.Close()
+
+	for _, expected := range []string{"allPlayers", "scoreUpdate"} {
+		if !stream.Receive() {
+			t.Fatalf("Expected %s; got %v", expected, stream.Err())
+		}
+		if stream.Msg().Type != expected {
+			t.Fatalf("Expected %s; got %+v", expected, stream.Msg())
+		}
+		players := stream.Msg().GetPlayers().GetPlayers()
+		if len(players) != 1 || players[0].Name != "Streamer" || players[0].SessionId != game["sessionId"] {
+			t.Errorf("Expected the scores of the owner in %s; got %+v", expected, stream.Msg())
+		}
+	}
+}
+
+// The questions of every game in the game service tests
+type staticQuestionSource []models.Question
+
+func (source staticQuestionSource) Questions(category string) ([]models.Question, error) {
+	return append([]models.Question{}, source...), nil
+}
+
+// Records the events of the game service instead of sending them to the hub
+type recordingPublisher struct {
+	events []string
+}
+
+func (publisher *recordingPublisher) PlayerJoined(gameServer *models.GameServer, session *models.PlayerSession) {
+	publisher.events = append(publisher.events, "playerJoined")
+}
+
+func (publisher *recordingPublisher) PowerUpUsed(gameServer *models.GameServer, session *models.PlayerSession, powerUp models.PowerUp) {
+	publisher.events = append(publisher.events, "powerUpUsed")
+}
+
+func (publisher *recordingPublisher) ScoresChanged(gameServer *models.GameServer) {
+	publisher.events = append(publisher.events, "scoresChanged")
+}
+
+func (publisher *recordingPublisher) GameFinished(gameServer *models.GameServer) {
+	publisher.events = append(publisher.events, "gameFinished")
+}
+
+func (publisher *recordingPublisher) LobbyChanged() {
+	publisher.events = append(publisher.events, "lobbyChanged")
+}
+
+func TestGameService(t *testing.T) {
+	questions := staticQuestionSource{
+		{ID: "service-1", Category: "Service", QuestionText: "First?", Options: []string{"a", "b", "c", "d"}, CorrectIndex: 2},
+		{ID: "service-2", Category: "Service", QuestionText: "Second?", Options: []string{"a", "b", "c", "d"}, CorrectIndex: 1},
+	}
+	repository := models.NewMemoryGameRepository()
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	clock := models.NewFakeClock(now)
+	publisher := &recordingPublisher{}
+	stores := controllers.NewGameStores()
+	service := controllers.NewGameService(repository, clock, 1, questions, publisher, stores)
+
+	started, err := service.StartGame(controllers.StartGameRequest{Name: "Unit Ursula", Questions: 2, PlayerID: "unit-ursula"})
+	if err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	gameServer, err := repository.Get(started.GameID)
+	if err != nil {
+		t.Fatalf("Expected the game in the repository: %v", err)
+	}
+	if !gameServer.Created.Equal(now) || !gameServer.Started.Equal(now) {
+		t.Errorf("Expected the game to be created and started at %v; got %v and %v", now, gameServer.Created, gameServer.Started)
+	}
+	if _, exists := models.GameServers[started.GameID]; exists {
+		t.Errorf("Expected the game to stay out of the shared game servers")
+	}
+
+	_, err = service.JoinGame(controllers.JoinGameRequest{GameID: started.GameID, Name: "Intruder"})
+	var apiError *controllers.APIError
+	if !errors.As(err, &apiError) || apiError.Code != controllers.CodeSinglePlayerGame {
+		t.Errorf("Expected joining a single player game to fail; got %v", err)
+	}
+
+	// Question counts are checked against the questions of this service
+	_, err = service.StartGame(controllers.StartGameRequest{Name: "Greedy Gus", Questions: 3, PlayerID: "greedy-gus"})
+	if !errors.As(err, &apiError) || apiError.Code != controllers.CodeValidationFailed {
+		t.Errorf("Expected more questions than the service has to be rejected; got %v", err)
+	}
+
+	// A daily attempt without the issued identity is refused, a new ID would be a new attempt
+	_, err = service.StartGame(controllers.StartGameRequest{Name: "Anonymous Ann", Mode: models.GameModeDaily})
+	if !errors.As(err, &apiError) || apiError.Code != controllers.CodeDailyNeedsIdentity || apiError.Status != http.StatusUnauthorized {
+		t.Errorf("Expected the daily challenge to need an identity; got %v", err)
+	}
+
+	// Double points doubles the next answer that scores
+	powerUp, err := service.UsePowerUp(controllers.PowerUpRequest{GameID: started.GameID, SessionID: started.SessionID, PowerUp: models.PowerUpDoublePoints})
+	if err != nil || !powerUp.DoublePoints {
+		t.Fatalf("Expected double points; got %+v, %v", powerUp, err)
+	}
+
+	expectedScores := []int{20, 30}
+	for i, question := range gameServer.Questions {
+		answer, _ := json.Marshal(question.CorrectIndex)
+		result, err := service.SubmitAnswer(controllers.AnswerRequest{GameID: started.GameID, SessionID: started.SessionID, QuestionID: question.ID, Answer: answer})
+		if err != nil {
+			t.Fatalf("Failed to submit answer: %v", err)
+		}
+		if !result.Correct || result.CurrentScore != expectedScores[i] {
+			t.Errorf("Expected a correct answer and a score of %d; got %+v", expectedScores[i], result)
+		}
+	}
+
+	session, _ := gameServer.Sessions.GetSession(started.SessionID)
+	if session.Answers[0].AnsweredAt != now {
+		t.Errorf("Expected answers at %v; got %v", now, session.Answers[0].AnsweredAt)
+	}
+
+	gameEnd, err := service.EndGame(started.GameID, started.SessionID)
+	if err != nil {
+		t.Fatalf("Failed to end game: %v", err)
+	}
+	if gameEnd.FinalScore != 30 || !gameEnd.Finished {
+		t.Errorf("Expected a finished game with a score of 30; got %+v", gameEnd)
+	}
+
+	expectedEvents := []string{"powerUpUsed", "scoresChanged", "scoresChanged", "gameFinished"}
+	if fmt.Sprint(publisher.events) != fmt.Sprint(expectedEvents) {
+		t.Errorf("Expected events %v; got %v", expectedEvents, publisher.events)
+	}
+
+	// The results only go to the stores of the service
+	if _, exists := stores.Archive.Profile("unit-ursula", stores.Ratings); !exists {
+		t.Errorf("Expected the game in the archive of the service")
+	}
+	if entries := stores.Leaderboards.Ranked(models.LeaderboardAllTime, models.SortByScore, now, stores.Ratings); len(entries) != 1 || entries[0].BestScore != 30 {
+		t.Errorf("Expected the result on the leaderboard of the service; got %+v", entries)
+	}
+	if _, exists := models.GameArchive.Profile("unit-ursula", models.Ratings); exists {
+		t.Errorf("Expected the game to stay out of the shared archive")
+	}
+	for _, entry := range models.Leaderboards.Ranked(models.LeaderboardAllTime, models.SortByScore, now, models.Ratings) {
+		if entry.PlayerID == "unit-ursula" {
+			t.Errorf("Expected the result to stay off the shared leaderboard")
+		}
+	}
+}
+
+func TestSeededGames(t *testing.T) {
+	questions := staticQuestionSource{}
+	for i := 0; i < 6; i++ {
+		questions = append(questions, models.Question{ID: fmt.Sprintf("seeded-%d", i), Category: "Seeded", QuestionText: "Which?", Options: []string{"a", "b", "c", "d", "e"}, CorrectIndex: i % 5})
+	}
+
+	// Services with the same seed pick the same questions in the same order and remove the same options
+	play := func(seed int64) (string, []int) {
+		service := controllers.NewGameService(models.NewMemoryGameRepository(), models.NewFakeClock(time.Now()), seed, questions, &recordingPublisher{}, controllers.NewGameStores())
+		started, err := service.StartGame(controllers.StartGameRequest{Name: "Seeded Sam", Questions: 6})
+		if err != nil {
+			t.Fatalf("Failed to start game: %v", err)
+		}
+		game, _ := service.GetGame(started.GameID, started.SessionID)
+		order := make([]string, 0, len(game.Questions))
+		for _, question := range game.Questions {
+			order = append(order, question.ID)
+		}
+
+		result, err := service.UsePowerUp(controllers.PowerUpRequest{GameID: started.GameID, SessionID: started.SessionID, PowerUp: models.PowerUpFiftyFifty})
+		if err != nil {
+			t.Fatalf("Failed to use 50/50: %v", err)
+		}
+		return strings.Join(order, ","), result.RemovedOptions
+	}
+
+	firstOrder, firstRemoved := play(42)
+	secondOrder, secondRemoved := play(42)
+	if firstOrder != secondOrder || fmt.Sprint(firstRemoved) != fmt.Sprint(secondRemoved) {
+		t.Errorf("Expected the same game for the same seed; got %s %v and %s %v", firstOrder, firstRemoved, secondOrder, secondRemoved)
+	}
+}
+
+func TestGameCountdown(t *testing.T) {
+	game := startGame(t, map[string]interface{}{"name": "Countdown Carl", "multiplayer": true, "questions": 2})
+	gameServer := models.GameServers[game["gameId"]]
+
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	clock := models.NewFakeClock(now)
+	hub := models.NewHub(clock)
+	go hub.Run()
+
+	client := models.NewStreamClient(game["gameId"], game["sessionId"])
+	hub.Register <- client
+	receive := func() models.Message {
+		select {
+		case message := <-client.Send:
+			return message
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for a message")
+			return models.Message{}
+		}
+	}
+
+	hub.Broadcast <- models.Message{Type: "startGame", ID: game["gameId"]}
+	deadline := time.Now().Add(time.Second)
+	for clock.Tickers() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the countdown to start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Every second of the clock counts down once, no matter how long the test takes
+	for secondsLeft := 10; secondsLeft >= 0; secondsLeft-- {
+		clock.Advance(time.Second)
+		message := receive()
+		var content map[string]string
+		json.Unmarshal([]byte(message.Content), &content)
+		if message.Type != "startGameCountdown" || content["secondsLeft"] != fmt.Sprint(secondsLeft) {
+			t.Fatalf("Expected %d seconds left; got %+v", secondsLeft, message)
+		}
+	}
+
+	if message := receive(); message.Type != "startGame" {
+		t.Fatalf("Expected the game to start; got %+v", message)
+	}
+	startsAt := now.Add(11 * time.Second)
+	if !gameServer.StartsAt.Equal(startsAt) || !gameServer.Started.Equal(startsAt) {
+		t.Errorf("Expected the game to start at %v; got %v, started %v", startsAt, gameServer.StartsAt, gameServer.Started)
+	}
+	if clock.Tickers() != 0 {
+		t.Errorf("Expected the countdown ticker to be stopped")
+	}
+}
+
+// Run with -race, a subscriber leaving while a message is sent to its room must not panic or race
+func TestHubUnsubscribeWhileBroadcasting(t *testing.T) {
+	hub := models.NewHub(models.SystemClock)
+	go hub.Run()
+
+	var clients []*models.StreamClient
+	for i := 0; i < 50; i++ {
+		client := models.NewStreamClient("busy-room", fmt.Sprint("session-", i))
+		hub.Register <- client
+		clients = append(clients, client)
+	}
+
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			for _, client := range hub.GetAllClients("busy-room") {
+				client.Deliver(models.Message{Type: "scoreUpdate", ID: "busy-room"})
+			}
+		}
+	}()
+	for _, client := range clients {
+		hub.Unregister <- client
+	}
+	<-done
+
+	deadline := time.Now().Add(time.Second)
+	for len(hub.GetAllClients("busy-room")) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected every client to leave the room")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if clients[0].Deliver(models.Message{Type: "scoreUpdate"}) {
+		t.Fatalf("Expected no delivery to a closed client")
+	}
+	clients[0].Close()
+}
+
+// How long a websocket test waits for the next message
+const wsTimeout = 2 * time.Second
+
+// wsPlayer is a player of a multiplayer game connected to its websocket
+type wsPlayer struct {
+	t         *testing.T
+	name      string
+	sessionID string
+	conn      *websocket.Conn
+	messages  chan models.Message
+}
+
+func connectPlayer(t *testing.T, gameID string, name string, sessionID string) *wsPlayer {
+	url := "ws" + strings.TrimPrefix(testServer.URL, "http") + routes.APIPrefix + "/game/" + gameID + "/ws?sessionId=" + sessionID
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect %s to the game: %v", name, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	player := &wsPlayer{t: t, name: name, sessionID: sessionID, conn: conn, messages: make(chan models.Message, 64)}
+	go func() {
+		defer close(player.messages)
+		for {
+			var message models.Message
+			if err := conn.ReadJSON(&message); err != nil {
+				return
+			}
+			player.messages <- message
+		}
+	}()
+	return player
+}
+
+func (player *wsPlayer) send(message models.Message) {
+	if err := player.conn.WriteJSON(message); err != nil {
+		player.t.Fatalf("Failed to send %s for %s: %v", message.Type, player.name, err)
+	}
+}
+
+// next waits for the next message of the player
+func (player *wsPlayer) next() models.Message {
+	select {
+	case message, ok := <-player.messages:
+		if !ok {
+			player.t.Fatalf("The connection of %s closed", player.name)
+		}
+		return message
+	case <-time.After(wsTimeout):
+		player.t.Fatalf("Timed out waiting for a message for %s", player.name)
+	}
+	return models.Message{}
+}
+
+// expect checks that the next messages of the player have the types in order and returns them
+func (player *wsPlayer) expect(types ...string) []models.Message {
+	messages := make([]models.Message, 0, len(types))
+	for _, expected := range types {
+		message := player.next()
+		if message.Type != expected {
+			player.t.Fatalf("Expected %s for %s; got %+v", expected, player.name, message)
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+// wsGame is a multiplayer game with every player connected to its websocket, the first player owns it
+type wsGame struct {
+	t       *testing.T
+	id      string
+	players []*wsPlayer
+	clock   *models.FakeClock
+}
+
+// newWSGame starts a multiplayer game and connects the named players one after the other. Each player sees
+// the players and scores when connecting and the players that were connected see them join.
+// The countdown of the game runs on a fake clock.
+func newWSGame(t *testing.T, questions int, names ...string) *wsGame {
+	clock := models.NewFakeClock(time.Now())
+	hub := models.NewHub(clock)
+	go hub.Run()
+	replaced := models.InstallHub(hub)
+	t.Cleanup(func() { models.InstallHub(replaced) })
+
+	started := startGame(t, map[string]interface{}{"name": names[0], "multiplayer": true, "questions": questions})
+	game := &wsGame{t: t, id: started["gameId"], clock: clock}
+	game.players = append(game.players, connectPlayer(t, game.id, names[0], started["sessionId"]))
+	game.players[0].expect("allPlayers", "scoreUpdate")
+
+	for _, name := range names[1:] {
+		status, joined := postJSON(t, "/game/join", map[string]interface{}{"gameId": game.id, "name": name})
+		if status != http.StatusOK {
+			t.Fatalf("Failed to join %s to the game: %v", name, status)
+		}
+		for _, player := range game.players {
+			player.expect("playerJoined")
+		}
+		game.players = append(game.players, connectPlayer(t, game.id, name, joined["sessionId"].(string)))
+		game.players[len(game.players)-1].expect("allPlayers", "scoreUpdate")
+	}
+	return game
+}
+
+// expectAll checks the next messages of every player
+func (game *wsGame) expectAll(types ...string) [][]models.Message {
+	messages := make([][]models.Message, 0, len(game.players))
+	for _, player := range game.players {
+		messages = append(messages, player.expect(types...))
+	}
+	return messages
+}
+
+// start has the owner start the game and fast-forwards the countdown
+func (game *wsGame) start() {
+	game.players[0].send(models.Message{Type: "startGame", ID: game.id})
+
+	deadline := time.Now().Add(wsTimeout)
+	for game.clock.Tickers() == 0 {
+		if time.Now().After(deadline) {
+			game.t.Fatalf("Expected the countdown to start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	for secondsLeft := 10; secondsLeft >= 0; secondsLeft-- {
+		game.clock.Advance(time.Second)
+		for _, messages := range game.expectAll("startGameCountdown") {
+			var content map[string]string
+			json.Unmarshal([]byte(messages[0].Content), &content)
+			if content["secondsLeft"] != strconv.Itoa(secondsLeft) {
+				game.t.Fatalf("Expected %d seconds left; got %+v", secondsLeft, messages[0])
+			}
+		}
+	}
+	game.expectAll("startGame")
+}
+
+// scores reads the score of every player from a scoreUpdate or gameFinished message
+func scores(t *testing.T, message models.Message) map[string]string {
+	var players []map[string]string
+	if err := json.Unmarshal([]byte(message.Content), &players); err != nil {
+		t.Fatalf("Failed to decode the scores of %+v: %v", message, err)
+	}
+	scores := make(map[string]string, len(players))
+	for _, player := range players {
+		scores[player["name"]] = player["score"]
+	}
+	return scores
+}
+
+func TestWebSocketMultiplayerGame(t *testing.T) {
+	game := newWSGame(t, 2, "Socket Sally", "Socket Sid", "Socket Sue")
+	game.start()
+
+	gameServer := models.GameServers[game.id]
+	if gameServer.Started.IsZero() {
+		t.Fatalf("Expected the game to be started")
+	}
+
+	answer := func(player *wsPlayer, question models.Question) {
+		status, _ := postJSON(t, "/answer", map[string]interface{}{"gameId": game.id, "sessionId": player.sessionID, "questionId": question.ID, "answer": question.Solution()})
+		if status != http.StatusOK {
+			t.Fatalf("Failed to answer for %s: %v", player.name, status)
+		}
+		game.expectAll("scoreUpdate")
+	}
+	end := func(player *wsPlayer) {
+		status, _ := postJSON(t, "/game/end", map[string]interface{}{"gameId": game.id, "sessionId": player.sessionID})
+		if status != http.StatusOK {
+			t.Fatalf("Failed to end the game for %s: %v", player.name, status)
+		}
+	}
+	sally, sid, sue := game.players[0], game.players[1], game.players[2]
+
+	// Sally answers every question first, the others answer too late to score
+	for _, question := range gameServer.Questions {
+		answer(sally, question)
+	}
+	for _, question := range gameServer.Questions {
+		answer(sue, question)
+	}
+	answer(sid, gameServer.Questions[0])
+
+	// The game goes on while a player has questions left
+	end(sally)
+	game.expectAll("scoreUpdate")
+	answer(sid, gameServer.Questions[1])
+	end(sid)
+
+	expected := map[string]string{"Socket Sally": "20", "Socket Sid": "0", "Socket Sue": "0"}
+	for _, messages := range game.expectAll("gameFinished") {
+		if got := scores(t, messages[0]); fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Errorf("Expected final scores %v; got %v", expected, got)
+		}
+	}
+}
+
+func TestAllowedOrigin(t *testing.T) {
+	// Without ALLOWED_ORIGINS only the local frontend may send the player cookie
+	local := allowedOrigin("")
+	if !local("http://localhost:3000") || local("https://evil.example") {
+		t.Fatalf("Expected only the local frontend to be allowed by default")
+	}
+
+	configured := allowedOrigin("https://captrivia.example, https://www.captrivia.example")
+	if !configured("https://www.captrivia.example") || configured("http://localhost:3000") {
+		t.Fatalf("Expected only the configured origins to be allowed")
+	}
+}
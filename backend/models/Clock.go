@@ -0,0 +1,176 @@
+package models
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time and ticks, it is injected so tests can choose what time it is
+type Clock interface {
+	Now() time.Time
+	// NewTicker ticks every period until it is stopped, like time.NewTicker
+	NewTicker(period time.Duration) Ticker
+	// AfterFunc calls f in its own goroutine once the duration has passed, like time.AfterFunc
+	AfterFunc(duration time.Duration, f func()) Timer
+}
+
+// Timer is the part of time.Timer that deadlines use, Stop returns false when f has already been called
+type Timer interface {
+	Stop() bool
+}
+
+// Ticker is the part of time.Ticker that games use
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(period time.Duration) Ticker {
+	return systemTicker{time.NewTicker(period)}
+}
+
+func (systemClock) AfterFunc(duration time.Duration, f func()) Timer {
+	return time.AfterFunc(duration, f)
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (ticker systemTicker) C() <-chan time.Time {
+	return ticker.Ticker.C
+}
+
+// SystemClock is the clock of the machine
+var SystemClock Clock = systemClock{}
+
+// FakeClock only moves when it is advanced, tests use it to fast-forward countdowns and time limits
+type FakeClock struct {
+	lock    sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+	timers  []*fakeTimer
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (clock *FakeClock) Now() time.Time {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	return clock.now
+}
+
+func (clock *FakeClock) NewTicker(period time.Duration) Ticker {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	ticker := &fakeTicker{clock: clock, period: period, next: clock.now.Add(period), c: make(chan time.Time, 1)}
+	clock.tickers = append(clock.tickers, ticker)
+	return ticker
+}
+
+func (clock *FakeClock) AfterFunc(duration time.Duration, f func()) Timer {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	timer := &fakeTimer{clock: clock, at: clock.now.Add(duration), f: f}
+	clock.timers = append(clock.timers, timer)
+	return timer
+}
+
+// Tickers returns the number of tickers that have not been stopped
+func (clock *FakeClock) Tickers() int {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	return len(clock.tickers)
+}
+
+// Advance moves the clock forward, fires the tickers and calls the functions of the timers that came due.
+// Like time.Ticker, a tick is dropped when the last one has not been received yet. Unlike time.AfterFunc,
+// the functions are called before Advance returns so tests can check what they did.
+func (clock *FakeClock) Advance(duration time.Duration) {
+	for _, f := range clock.advance(duration) {
+		f()
+	}
+}
+
+// advance moves the clock and fires the tickers, the functions of the timers that came due are returned
+func (clock *FakeClock) advance(duration time.Duration) []func() {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	clock.now = clock.now.Add(duration)
+	for _, ticker := range clock.tickers {
+		for !ticker.next.After(clock.now) {
+			select {
+			case ticker.c <- ticker.next:
+			default:
+			}
+			ticker.next = ticker.next.Add(ticker.period)
+		}
+	}
+
+	var due []func()
+	pending := clock.timers[:0]
+	for _, timer := range clock.timers {
+		if timer.at.After(clock.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		due = append(due, timer.f)
+	}
+	clock.timers = pending
+	return due
+}
+
+type fakeTicker struct {
+	clock  *FakeClock
+	period time.Duration
+	next   time.Time
+	c      chan time.Time
+}
+
+func (ticker *fakeTicker) C() <-chan time.Time {
+	return ticker.c
+}
+
+func (ticker *fakeTicker) Stop() {
+	ticker.clock.lock.Lock()
+	defer ticker.clock.lock.Unlock()
+
+	for i, other := range ticker.clock.tickers {
+		if other == ticker {
+			ticker.clock.tickers = append(ticker.clock.tickers[:i], ticker.clock.tickers[i+1:]...)
+			break
+		}
+	}
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	f     func()
+}
+
+func (timer *fakeTimer) Stop() bool {
+	timer.clock.lock.Lock()
+	defer timer.clock.lock.Unlock()
+
+	for i, other := range timer.clock.timers {
+		if other == timer {
+			timer.clock.timers = append(timer.clock.timers[:i], timer.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
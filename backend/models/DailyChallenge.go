@@ -0,0 +1,141 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/utils"
+)
+
+// Number of questions in the daily challenge
+const DailyChallengeQuestions = 10
+
+var ErrDailyAttemptUsed = errors.New("daily challenge already attempted today")
+
+// DailyKey returns the calendar day, in UTC, that a time belongs to
+func DailyKey(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+type DailyAttempt struct {
+	PlayerID string    `json:"playerId"`
+	Name     string    `json:"name"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Score    int       `json:"score"`
+	Correct  int       `json:"correct"`
+}
+
+type DailyLeaderboardEntry struct {
+	Rank     int       `json:"rank"`
+	PlayerID string    `json:"playerId"`
+	Name     string    `json:"name"`
+	Score    int       `json:"score"`
+	Correct  int       `json:"correct"`
+	Seconds  float64   `json:"seconds"`
+	Finished time.Time `json:"finished"`
+}
+
+// DailyChallengeStore keeps the daily challenge attempts of every player and saves them to disk after every change
+type DailyChallengeStore struct {
+	sync.Mutex
+	path string
+	// Attempts keyed by day and then by player ID
+	Days map[string]map[string]*DailyAttempt `json:"days"`
+}
+
+// DailyChallenges is the global record of daily challenge attempts
+var DailyChallenges = NewDailyChallengeStore()
+
+func NewDailyChallengeStore() *DailyChallengeStore {
+	return &DailyChallengeStore{Days: make(map[string]map[string]*DailyAttempt)}
+}
+
+// Load reads the saved attempts from path and keeps saving to it from then on
+func (store *DailyChallengeStore) Load(path string) error {
+	store.Lock()
+	defer store.Unlock()
+
+	store.path = path
+	return utils.ReadJSONFile(path, store)
+}
+
+func (store *DailyChallengeStore) save() error {
+	if store.path == "" {
+		return nil
+	}
+	return utils.WriteJSONFile(store.path, store)
+}
+
+// StartAttempt uses up the player's attempt for the day, it fails if they already had one.
+// The player ID has to be the identity the server issued, an ID a client chose would give it as many attempts as it likes.
+func (store *DailyChallengeStore) StartAttempt(day string, playerID string, name string, at time.Time) error {
+	store.Lock()
+	defer store.Unlock()
+
+	attempts, exists := store.Days[day]
+	if !exists {
+		attempts = make(map[string]*DailyAttempt)
+		store.Days[day] = attempts
+	}
+
+	if _, attempted := attempts[playerID]; attempted {
+		return ErrDailyAttemptUsed
+	}
+	attempts[playerID] = &DailyAttempt{PlayerID: playerID, Name: name, Started: at}
+	return store.save()
+}
+
+// RecordResult stores the final score of the player's attempt for the day
+func (store *DailyChallengeStore) RecordResult(day string, playerID string, result GameResult, finished time.Time) error {
+	store.Lock()
+	defer store.Unlock()
+
+	attempt, exists := store.Days[day][playerID]
+	if !exists || !attempt.Finished.IsZero() {
+		return nil
+	}
+
+	attempt.Score = result.Score
+	attempt.Correct = result.Correct
+	attempt.Finished = finished
+	return store.save()
+}
+
+// Leaderboard ranks the finished attempts of a day by score, faster attempts win ties
+func (store *DailyChallengeStore) Leaderboard(day string) []DailyLeaderboardEntry {
+	store.Lock()
+	entries := make([]DailyLeaderboardEntry, 0, len(store.Days[day]))
+	for _, attempt := range store.Days[day] {
+		if attempt.Finished.IsZero() {
+			continue
+		}
+		entries = append(entries, DailyLeaderboardEntry{
+			PlayerID: attempt.PlayerID,
+			Name:     attempt.Name,
+			Score:    attempt.Score,
+			Correct:  attempt.Correct,
+			Seconds:  attempt.Finished.Sub(attempt.Started).Seconds(),
+			Finished: attempt.Finished,
+		})
+	}
+	store.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Seconds != b.Seconds {
+			return a.Seconds < b.Seconds
+		}
+		return a.PlayerID < b.PlayerID
+	})
+
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries
+}
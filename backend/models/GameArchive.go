@@ -0,0 +1,362 @@
+package models
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/utils"
+)
+
+const (
+	// Number of recent games shown in the accuracy trend of a profile
+	accuracyTrendLength = 10
+	// Number of finished games kept by default, the oldest ones are dropped first
+	DefaultArchiveLimit = 10000
+)
+
+type ArchivedQuestion struct {
+	ID           string       `json:"id"`
+	Category     string       `json:"category"`
+	QuestionText string       `json:"questionText"`
+	Options      []string     `json:"options"`
+	CorrectIndex int          `json:"correctIndex"`
+	Type         QuestionType `json:"type,omitempty"`
+	// Correct answer in the same shape as an answer to the question
+	CorrectAnswer interface{} `json:"correctAnswer"`
+	Explanation   string      `json:"explanation"`
+	// Seed of a generated question, it can be built again from it
+	Seed           int64  `json:"seed,omitempty"`
+	CorrectSession string `json:"correctSession"`
+}
+
+type ArchivedPlayer struct {
+	SessionID string         `json:"sessionId"`
+	PlayerID  string         `json:"playerId"`
+	Name      string         `json:"name"`
+	Score     int            `json:"score"`
+	Correct   int            `json:"correct"`
+	Rank      int            `json:"rank"`
+	Answers   []AnswerRecord `json:"answers"`
+}
+
+// ArchivedGame is the record of a finished game kept after the game server is gone
+type ArchivedGame struct {
+	ID          string             `json:"id"`
+	Multiplayer bool               `json:"multiplayer"`
+	Category    string             `json:"category"`
+	Started     time.Time          `json:"started"`
+	Finished    time.Time          `json:"finished"`
+	Questions   []ArchivedQuestion `json:"questions"`
+	Players     []ArchivedPlayer   `json:"players"`
+	// Day of the daily challenge, only set for daily games
+	Day string `json:"day,omitempty"`
+}
+
+// PlayerAnswer is an archived answer together with the question it was for
+type PlayerAnswer struct {
+	QuestionID    string          `json:"questionId"`
+	QuestionText  string          `json:"questionText"`
+	Answer        json.RawMessage `json:"answer"`
+	CorrectIndex  int             `json:"correctIndex"`
+	CorrectAnswer interface{}     `json:"correctAnswer"`
+	Correct       bool            `json:"correct"`
+	Scored        bool            `json:"scored"`
+}
+
+// PlayerGame is a finished game from the point of view of one player
+type PlayerGame struct {
+	GameID      string         `json:"gameId"`
+	Multiplayer bool           `json:"multiplayer"`
+	Category    string         `json:"category"`
+	Day         string         `json:"day,omitempty"`
+	Finished    time.Time      `json:"finished"`
+	Players     int            `json:"players"`
+	Rank        int            `json:"rank"`
+	Score       int            `json:"score"`
+	Correct     int            `json:"correct"`
+	Answered    int            `json:"answered"`
+	Accuracy    float64        `json:"accuracy"`
+	Answers     []PlayerAnswer `json:"answers"`
+	// The answers are left out while the daily challenge of the game can still be played
+	AnswersHidden bool `json:"answersHidden,omitempty"`
+}
+
+type AccuracyPoint struct {
+	GameID   string    `json:"gameId"`
+	Finished time.Time `json:"finished"`
+	Accuracy float64   `json:"accuracy"`
+}
+
+type PlayerProfile struct {
+	PlayerID    string  `json:"playerId"`
+	Name        string  `json:"name"`
+	GamesPlayed int     `json:"gamesPlayed"`
+	Wins        int     `json:"wins"`
+	TotalScore  int     `json:"totalScore"`
+	BestScore   int     `json:"bestScore"`
+	Correct     int     `json:"correct"`
+	Answered    int     `json:"answered"`
+	Accuracy    float64 `json:"accuracy"`
+	Rating      float64 `json:"rating"`
+	// Accuracy of the most recent games, oldest first
+	AccuracyTrend []AccuracyPoint `json:"accuracyTrend"`
+}
+
+// GameArchiveStore keeps finished games and appends every new one to its file, one game per line
+type GameArchiveStore struct {
+	sync.Mutex
+	path string
+	// Lines in the file, dropped games are only removed from it once they take up half of it
+	lines int
+	Games []*ArchivedGame `json:"games"`
+	// Most games kept, profiles and match histories only cover these
+	Limit int `json:"-"`
+}
+
+// GameArchive is the global archive of finished games
+var GameArchive = NewGameArchiveStore()
+
+func NewGameArchiveStore() *GameArchiveStore {
+	return &GameArchiveStore{Games: make([]*ArchivedGame, 0), Limit: DefaultArchiveLimit}
+}
+
+// Load reads the saved games from path and keeps appending to it from then on
+func (archive *GameArchiveStore) Load(path string) error {
+	archive.Lock()
+	defer archive.Unlock()
+
+	archive.path = path
+	legacy := false
+	err := utils.ReadJSONLines(path, func(line []byte) error {
+		// Archives saved before games were appended hold every game in a single {"games": [...]} object
+		var saved struct {
+			Games []*ArchivedGame `json:"games"`
+		}
+		if err := json.Unmarshal(line, &saved); err != nil {
+			return err
+		}
+		if saved.Games != nil {
+			archive.Games = append(archive.Games, saved.Games...)
+			legacy = true
+			return nil
+		}
+
+		game := &ArchivedGame{}
+		if err := json.Unmarshal(line, game); err != nil {
+			return err
+		}
+		archive.Games = append(archive.Games, game)
+		archive.lines++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	archive.trim()
+	if legacy {
+		return archive.compact()
+	}
+	return nil
+}
+
+// trim drops the oldest games over the limit, the caller must hold the lock
+func (archive *GameArchiveStore) trim() {
+	if excess := len(archive.Games) - archive.Limit; archive.Limit > 0 && excess > 0 {
+		archive.Games = append([]*ArchivedGame{}, archive.Games[excess:]...)
+	}
+}
+
+// compact rewrites the file with only the games that are kept, the caller must hold the lock
+func (archive *GameArchiveStore) compact() error {
+	games := make([]interface{}, len(archive.Games))
+	for i, game := range archive.Games {
+		games[i] = game
+	}
+	if err := utils.WriteJSONLines(archive.path, games); err != nil {
+		return err
+	}
+	archive.lines = len(archive.Games)
+	return nil
+}
+
+// Archive stores the questions and the results of every session of a finished game
+func (archive *GameArchiveStore) Archive(gameServer *GameServer) error {
+	game := &ArchivedGame{
+		ID:          gameServer.ID,
+		Multiplayer: gameServer.Multiplayer,
+		Category:    gameServer.Settings.Category,
+		Day:         gameServer.Settings.Day,
+		Started:     gameServer.Started,
+		Finished:    gameServer.Finished,
+		Questions:   make([]ArchivedQuestion, 0, len(gameServer.Questions)),
+		Players:     make([]ArchivedPlayer, 0),
+	}
+
+	for _, question := range gameServer.Questions {
+		game.Questions = append(game.Questions, ArchivedQuestion{
+			ID:             question.ID,
+			Category:       question.Category,
+			QuestionText:   question.QuestionText,
+			Options:        question.Options,
+			CorrectIndex:   question.CorrectIndex,
+			Type:           question.Kind(),
+			CorrectAnswer:  question.Solution(),
+			Explanation:    question.Explanation,
+			Seed:           question.Seed,
+			CorrectSession: question.CorrectSession,
+		})
+	}
+
+	ranked, ranks := RankSessions(gameServer.Sessions.All())
+	for i, session := range ranked {
+		game.Players = append(game.Players, ArchivedPlayer{
+			SessionID: session.ID,
+			PlayerID:  session.PlayerID,
+			Name:      session.Name,
+			Score:     session.Score,
+			Correct:   session.Correct,
+			Rank:      ranks[i],
+			Answers:   append([]AnswerRecord{}, session.Answers...),
+		})
+	}
+
+	archive.Lock()
+	defer archive.Unlock()
+
+	archive.Games = append(archive.Games, game)
+	archive.trim()
+	if archive.path == "" {
+		return nil
+	}
+
+	if err := utils.AppendJSONLine(archive.path, game); err != nil {
+		return err
+	}
+	archive.lines++
+	if archive.lines >= 2*len(archive.Games) {
+		return archive.compact()
+	}
+	return nil
+}
+
+func newPlayerGame(game *ArchivedGame, player ArchivedPlayer) PlayerGame {
+	questions := make(map[string]ArchivedQuestion, len(game.Questions))
+	for _, question := range game.Questions {
+		questions[question.ID] = question
+	}
+
+	answers := make([]PlayerAnswer, 0, len(player.Answers))
+	for _, answer := range player.Answers {
+		question := questions[answer.QuestionID]
+		answers = append(answers, PlayerAnswer{
+			QuestionID:    answer.QuestionID,
+			QuestionText:  question.QuestionText,
+			Answer:        answer.Answer,
+			CorrectIndex:  question.CorrectIndex,
+			CorrectAnswer: question.CorrectAnswer,
+			Correct:       answer.Correct,
+			Scored:        answer.Scored,
+		})
+	}
+
+	return PlayerGame{
+		GameID:      game.ID,
+		Multiplayer: game.Multiplayer,
+		Category:    game.Category,
+		Day:         game.Day,
+		Finished:    game.Finished,
+		Players:     len(game.Players),
+		Rank:        player.Rank,
+		Score:       player.Score,
+		Correct:     player.Correct,
+		Answered:    len(player.Answers),
+		Accuracy:    accuracyPercent(player.Correct, len(player.Answers)),
+		Answers:     answers,
+	}
+}
+
+// HideAnswers leaves out the answers of a daily challenge game when today's challenge is the one played,
+// the answers would give away the solutions to players who have not played it yet
+func (game *PlayerGame) HideAnswers(today string) {
+	if game.Day != "" && game.Day == today {
+		game.Answers = []PlayerAnswer{}
+		game.AnswersHidden = true
+	}
+}
+
+// GamesOfPlayer returns every archived game the player took part in, newest first
+func (archive *GameArchiveStore) GamesOfPlayer(playerID string) []PlayerGame {
+	archive.Lock()
+	defer archive.Unlock()
+
+	games := make([]PlayerGame, 0)
+	for _, game := range archive.Games {
+		for _, player := range game.Players {
+			if player.PlayerID == playerID {
+				games = append(games, newPlayerGame(game, player))
+			}
+		}
+	}
+
+	sort.SliceStable(games, func(i, j int) bool {
+		return games[i].Finished.After(games[j].Finished)
+	})
+	return games
+}
+
+// Profile sums up the archived games of a player with their rating, it returns false if the player has no finished games
+func (archive *GameArchiveStore) Profile(playerID string, ratings *RatingStore) (PlayerProfile, bool) {
+	games := archive.GamesOfPlayer(playerID)
+	if len(games) == 0 {
+		return PlayerProfile{}, false
+	}
+
+	archive.Lock()
+	name := ""
+	for i := len(archive.Games) - 1; i >= 0 && name == ""; i-- {
+		for _, player := range archive.Games[i].Players {
+			if player.PlayerID == playerID {
+				name = player.Name
+				break
+			}
+		}
+	}
+	archive.Unlock()
+
+	profile := PlayerProfile{
+		PlayerID:      playerID,
+		Name:          name,
+		GamesPlayed:   len(games),
+		Rating:        ratings.RatingOf(playerID),
+		AccuracyTrend: make([]AccuracyPoint, 0, accuracyTrendLength),
+	}
+	for _, game := range games {
+		profile.TotalScore += game.Score
+		profile.Correct += game.Correct
+		profile.Answered += game.Answered
+		if game.Score > profile.BestScore {
+			profile.BestScore = game.Score
+		}
+		if game.Multiplayer && game.Rank == 1 && game.Players > 1 {
+			profile.Wins++
+		}
+	}
+	profile.Accuracy = accuracyPercent(profile.Correct, profile.Answered)
+
+	// Games are newest first, the trend reads oldest first
+	trendLength := accuracyTrendLength
+	if len(games) < trendLength {
+		trendLength = len(games)
+	}
+	for i := trendLength - 1; i >= 0; i-- {
+		profile.AccuracyTrend = append(profile.AccuracyTrend, AccuracyPoint{
+			GameID:   games[i].GameID,
+			Finished: games[i].Finished,
+			Accuracy: games[i].Accuracy,
+		})
+	}
+	return profile, true
+}
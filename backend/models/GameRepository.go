@@ -0,0 +1,42 @@
+package models
+
+import "sync"
+
+// GameRepository stores the game servers
+type GameRepository interface {
+	// Get returns ErrGameNotFound for games that are not stored
+	Get(gameID string) (*GameServer, error)
+	Save(gameServer *GameServer)
+}
+
+// MemoryGameRepository keeps the game servers in a map
+type MemoryGameRepository struct {
+	lock  *sync.RWMutex
+	games map[string]*GameServer
+}
+
+// GameServerRepository stores games in GameServers, where the hub and the lobby look for them
+var GameServerRepository = &MemoryGameRepository{lock: &GameServersLock, games: GameServers}
+
+// NewMemoryGameRepository creates an empty repository, the games in it are not seen by the hub or the lobby
+func NewMemoryGameRepository() *MemoryGameRepository {
+	return &MemoryGameRepository{lock: &sync.RWMutex{}, games: make(map[string]*GameServer)}
+}
+
+func (repository *MemoryGameRepository) Get(gameID string) (*GameServer, error) {
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	gameServer, exists := repository.games[gameID]
+	if !exists {
+		return nil, ErrGameNotFound
+	}
+	return gameServer, nil
+}
+
+func (repository *MemoryGameRepository) Save(gameServer *GameServer) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	repository.games[gameServer.ID] = gameServer
+}
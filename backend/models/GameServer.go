@@ -1,47 +1,120 @@
 package models
 
 import (
+	"encoding/json"
 	"errors"
+	"math/rand"
+	"sync"
 	"time"
 )
 
+var ErrGameNotFound = errors.New("game server not found")
+
+// DefaultGameCapacity is the number of players allowed in a multiplayer game when the owner does not set one
+const DefaultGameCapacity = 8
+
 // Future improvement - store game servers in a database
 var GameServers = make(map[string]*GameServer)
 
+// GameServersLock guards access to the GameServers map
+var GameServersLock sync.RWMutex
+
+// GameMode decides how the questions of a game are picked
+type GameMode string
+
+const (
+	// Random questions from the bank
+	GameModeClassic GameMode = "classic"
+	// Single player game with the questions due in the player's spaced-repetition schedule
+	GameModePractice GameMode = "practice"
+	// Single player game with the same questions for everyone on a calendar day
+	GameModeDaily GameMode = "daily"
+)
+
+// IsValid returns true for the known modes, an empty mode falls back to GameModeClassic
+func (mode GameMode) IsValid() bool {
+	return mode == "" || mode == GameModeClassic || mode == GameModePractice || mode == GameModeDaily
+}
+
+// GameSettings holds the options chosen by the owner when the game is created
+type GameSettings struct {
+	Public bool
+	Capacity int
+	Category string
+	NamePolicy NamePolicy
+	Mode GameMode
+	// Day of the daily challenge, only set for daily games
+	Day string
+	// Tournament the game is a match of, only set for tournament games
+	TournamentID string
+	// Uses of each power-up every session gets, power-ups that are not listed cannot be used
+	PowerUps map[PowerUp]int
+}
+
 type GameServer struct {
+	// Guards the finished time and the claims on questions
+	lock sync.Mutex
 	Questions []Question
 	Sessions  *SessionStore
 	Multiplayer bool
+	Settings GameSettings
 	ID string
 	Owner string
+	Created time.Time
+	StartsAt time.Time
 	Started time.Time
 	Finished time.Time
+	// Tells the time of the game and picks the options of its power-ups, tests replace them to replay a game
+	Clock Clock
+	Random *rand.Rand
 }
 
-// CheckAnswer checks if the submitted answer is correct and if the question has already been answered
-// If the question has already been answered, it returns if the answer was correct and if the question has already been answered
-func (gameServer *GameServer) CheckAnswer(sessionID string, questionID string, submittedAnswer int) (bool, bool, error) {
-	for _, question := range gameServer.Questions {
+// MarkFinished marks the game as finished, it returns false if the game had already finished
+func (gameServer *GameServer) MarkFinished() bool {
+	gameServer.lock.Lock()
+	defer gameServer.lock.Unlock()
+
+	if !gameServer.Finished.IsZero() {
+		return false
+	}
+	gameServer.Finished = gameServer.Clock.Now()
+	return true
+}
+
+// IsFull returns true if the game has reached its player capacity
+func (gameServer *GameServer) IsFull() bool {
+	return gameServer.Settings.Capacity > 0 && gameServer.Sessions.Count() >= gameServer.Settings.Capacity
+}
+
+// CheckAnswer grades the submitted answer and checks if the question has already been answered correctly.
+// It returns the credit of the answer, from 0 to 1, and whether another session claimed the question first.
+// Only a fully correct answer claims the question.
+func (gameServer *GameServer) CheckAnswer(sessionID string, questionID string, submittedAnswer json.RawMessage) (float64, bool, error) {
+	gameServer.lock.Lock()
+	defer gameServer.lock.Unlock()
+
+	for i := range gameServer.Questions {
+		question := &gameServer.Questions[i]
 		if question.ID == questionID {
-			// Determine if the submitted answer is correct
-			correct := question.CorrectIndex == submittedAnswer
+			// Grade the submitted answer for the type of question
+			credit, err := question.Grade(submittedAnswer)
+			if err != nil {
+				return 0, false, err
+			}
 
 			// Check if the question has already been answered
 			if question.CorrectSession != "" {
-				return correct, true, nil
+				return credit, true, nil
 			}
 
 			// Mark the question as answered if the answer is correct
-			if correct {
+			if credit == 1 {
 				question.CorrectSession = sessionID
-				return correct, false, nil
 			}
-
-			// If the answer is incorrect
-			return correct, false, nil
+			return credit, false, nil
 		}
 	}
 	
 	// If the question is not found
-	return false, false, errors.New("question not found")
+	return 0, false, ErrQuestionNotFound
 }
\ No newline at end of file
@@ -10,14 +10,16 @@ import (
 // Hub is a struct that holds all the clients and the messages that are sent to them
 type Hub struct {
 	sync.Mutex
-	// Registered clients.
-	Clients map[string]map[*Client]bool
+	// Registered clients, websocket and SSE subscribers alike.
+	Clients map[string]map[Subscriber]bool
 	//Unregistered clients.
-	Unregister chan *Client
+	Unregister chan Subscriber
 	// Register requests from the clients.
-	Register chan *Client
+	Register chan Subscriber
 	// Inbound messages from the clients.
 	Broadcast chan Message
+	// Times the countdown before a game starts
+	Clock Clock
 }
 
 // Message struct to hold message data
@@ -30,21 +32,26 @@ type Message struct {
 }
 
 var hub *Hub //Singleton hub
+var hubLock sync.Mutex
 
 func GetOrCreateHub() *Hub {
+	hubLock.Lock()
+	defer hubLock.Unlock()
+
 	if hub == nil {
-		hub = NewHub()
+		hub = NewHub(SystemClock)
 		go hub.Run()
 	}
 	return hub
 }
 
-func NewHub() *Hub {
+func NewHub(clock Clock) *Hub {
 	return &Hub{
-		Clients:    make(map[string]map[*Client]bool),
-		Unregister: make(chan *Client),
-		Register:   make(chan *Client),
+		Clients:    make(map[string]map[Subscriber]bool),
+		Unregister: make(chan Subscriber),
+		Register:   make(chan Subscriber),
 		Broadcast:  make(chan Message),
+		Clock:      clock,
 	}
 }
 
@@ -68,11 +75,11 @@ func (h *Hub) Run() {
 }
 
 // GetAllClients function to retrieve all clients in the hub for a given client ID
-func (h *Hub) GetAllClients(clientID string) []*Client {
+func (h *Hub) GetAllClients(clientID string) []Subscriber {
 	h.Lock()
 	defer h.Unlock()
 	
-	var clients []*Client
+	var clients []Subscriber
 	connections := h.Clients[clientID]
 	for client := range connections {
 		clients = append(clients, client)
@@ -81,26 +88,50 @@ func (h *Hub) GetAllClients(clientID string) []*Client {
 }
 
 //function check if room exists and if not create it and add client to it
-func (h *Hub) RegisterNewClient(client *Client) {
+func (h *Hub) RegisterNewClient(client Subscriber) {
 	h.Lock()
 	defer h.Unlock()
 	
-	connections := h.Clients[client.ID]
+	connections := h.Clients[client.Room()]
 	if connections == nil {
-		connections = make(map[*Client]bool)
-		h.Clients[client.ID] = connections
+		connections = make(map[Subscriber]bool)
+		h.Clients[client.Room()] = connections
 	}
-	h.Clients[client.ID][client] = true
+	h.Clients[client.Room()][client] = true
 }
 
 //function to remvoe client from room
-func (h *Hub) RemoveClient(client *Client) {
+func (h *Hub) RemoveClient(client Subscriber) {
 	h.Lock()
 	defer h.Unlock()
 	
-	if _, ok := h.Clients[client.ID]; ok {
-		delete(h.Clients[client.ID], client)
-		close(client.Send)
+	if _, ok := h.Clients[client.Room()][client]; ok {
+		delete(h.Clients[client.Room()], client)
+		client.Close()
+	}
+}
+
+// RemoveSessionClients disconnects every client of the room that belongs to the given session
+func (h *Hub) RemoveSessionClients(roomID string, sessionID string) {
+	h.Lock()
+	defer h.Unlock()
+
+	for client := range h.Clients[roomID] {
+		if client.Session() == sessionID {
+			delete(h.Clients[roomID], client)
+			client.Close()
+		}
+	}
+}
+
+// deliver sends the message to every client of the room and drops the clients that cannot keep up,
+// the caller must hold the hub lock
+func (h *Hub) deliver(roomID string, message Message) {
+	for client := range h.Clients[roomID] {
+		if !client.Deliver(message) {
+			client.Close()
+			delete(h.Clients[roomID], client)
+		}
 	}
 }
 
@@ -110,19 +141,25 @@ func sendCountdownMessage(seconds int) Message {
 	 return Message{Type: "startGameCountdown", Content: string(contentValue)}
 }
 
-func sendStartGameMessage(clients map[*Client]bool) {
+func sendStartGameMessage() Message {
 	content := map[string]string{"message": "Game is starting"}
 	contentValue, _ := json.Marshal(content)
-	message := Message{Type: "startGame", Content: string(contentValue)}
+	return Message{Type: "startGame", Content: string(contentValue)}
+}
 
-	for client := range clients {
-		select {
-		case client.Send <- message:
-		default:
-			close(client.Send)
-			delete(clients, client)
-		}
-	}
+// sendLobbyUpdate pushes the list of joinable games to the lobby room, the caller must hold the hub lock
+func (h *Hub) sendLobbyUpdate() {
+	h.deliver(LobbyRoomID, NewLobbyUpdateMessage())
+}
+
+// InstallHub makes a running hub the one every handler uses and returns the hub it replaces, nil if there was none yet
+func InstallHub(installed *Hub) *Hub {
+	hubLock.Lock()
+	defer hubLock.Unlock()
+
+	replaced := hub
+	hub = installed
+	return replaced
 }
 
 //function to handle message based on type of message
@@ -132,24 +169,26 @@ func (h *Hub) HandleMessage(message Message) {
 	
 	//Check if the message is a type of "startGame"
 	if message.Type == "startGame" {
-		clients := h.Clients[message.ID]
-		ticker := time.NewTicker(time.Second)
+		GameServersLock.RLock()
+		gameServer, exists := GameServers[message.ID]
+		GameServersLock.RUnlock()
+		if !exists {
+			return
+		}
+
+		ticker := h.Clock.NewTicker(time.Second)
 
 		iterations := 10
-		for range ticker.C {
-			for client := range clients {
-				select {
-				case client.Send <- sendCountdownMessage(iterations):
-				default:
-					close(client.Send)
-					delete(h.Clients[message.ID], client)
-				}
-			}
+		gameServer.StartsAt = h.Clock.Now().Add(time.Duration(iterations+1) * time.Second)
+		h.sendLobbyUpdate()
+		for range ticker.C() {
+			h.deliver(message.ID, sendCountdownMessage(iterations))
 			iterations--
 			if iterations == -1 {
 				ticker.Stop()
-				GameServers[message.ID].Started = time.Now()
-				sendStartGameMessage(clients)
+				gameServer.Started = h.Clock.Now()
+				h.deliver(message.ID, sendStartGameMessage())
+				h.sendLobbyUpdate()
 				break
 			}
 		}
@@ -157,28 +196,12 @@ func (h *Hub) HandleMessage(message Message) {
 
 	//Check if the message is a type of "message"
 	if message.Type == "message" {
-		clients := h.Clients[message.ID]
-		for client := range clients {
-			select {
-			case client.Send <- message:
-			default:
-				close(client.Send)
-				delete(h.Clients[message.ID], client)
-			}
-		}
+		h.deliver(message.ID, message)
 	}
 
 	//Check if the message is a type of "notification"
 	if message.Type == "notification" {
-		clients := h.Clients[message.Recipient]
-		for client := range clients {
-			select {
-			case client.Send <- message:
-			default:
-				close(client.Send)
-				delete(h.Clients[message.Recipient], client)
-			}
-		}
+		h.deliver(message.Recipient, message)
 	}
 
 }
\ No newline at end of file
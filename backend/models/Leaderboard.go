@@ -0,0 +1,201 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/utils"
+)
+
+type LeaderboardWindow string
+
+const (
+	LeaderboardAllTime LeaderboardWindow = "all-time"
+	LeaderboardWeekly  LeaderboardWindow = "weekly"
+	LeaderboardDaily   LeaderboardWindow = "daily"
+)
+
+type LeaderboardSort string
+
+const (
+	SortByScore     LeaderboardSort = "score"
+	SortByAccuracy  LeaderboardSort = "accuracy"
+	SortByBestScore LeaderboardSort = "best"
+	SortByRating    LeaderboardSort = "rating"
+)
+
+func (window LeaderboardWindow) IsValid() bool {
+	return window == LeaderboardAllTime || window == LeaderboardWeekly || window == LeaderboardDaily
+}
+
+func (sortBy LeaderboardSort) IsValid() bool {
+	return sortBy == SortByScore || sortBy == SortByAccuracy || sortBy == SortByBestScore || sortBy == SortByRating
+}
+
+// GameResult is the outcome of a single finished session
+type GameResult struct {
+	PlayerID string
+	Name     string
+	Score    int
+	Correct  int
+	Answered int
+}
+
+// LeaderboardEntry holds the totals of a player within one leaderboard window
+type LeaderboardEntry struct {
+	Rank        int     `json:"rank"`
+	PlayerID    string  `json:"playerId"`
+	Name        string  `json:"name"`
+	GamesPlayed int     `json:"gamesPlayed"`
+	Correct     int     `json:"correct"`
+	Answered    int     `json:"answered"`
+	Accuracy    float64 `json:"accuracy"`
+	BestScore   int     `json:"bestScore"`
+	TotalScore  int     `json:"totalScore"`
+	Rating      float64 `json:"rating"`
+}
+
+// Leaderboard keeps player totals per window and saves them to disk after every change
+type Leaderboard struct {
+	sync.Mutex
+	path string
+	// Entries keyed by window bucket (e.g. "daily:2024-03-14") and then by player ID
+	Buckets map[string]map[string]*LeaderboardEntry `json:"buckets"`
+}
+
+// Leaderboards is the global leaderboard shared by all games
+var Leaderboards = NewLeaderboard()
+
+func NewLeaderboard() *Leaderboard {
+	return &Leaderboard{Buckets: make(map[string]map[string]*LeaderboardEntry)}
+}
+
+// Load reads the saved leaderboard from path and keeps saving to it from then on
+func (leaderboard *Leaderboard) Load(path string) error {
+	leaderboard.Lock()
+	defer leaderboard.Unlock()
+
+	leaderboard.path = path
+	return utils.ReadJSONFile(path, leaderboard)
+}
+
+// accuracyPercent returns the share of correct answers as a percentage with one decimal
+func accuracyPercent(correct int, answered int) float64 {
+	if answered == 0 {
+		return 0
+	}
+	return math.Round(float64(correct)/float64(answered)*1000) / 10
+}
+
+// bucketKey returns the bucket holding the totals of the window that contains t
+func bucketKey(window LeaderboardWindow, t time.Time) string {
+	t = t.UTC()
+	switch window {
+	case LeaderboardDaily:
+		return fmt.Sprintf("%s:%s", window, t.Format("2006-01-02"))
+	case LeaderboardWeekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%s:%d-W%02d", window, year, week)
+	default:
+		return string(LeaderboardAllTime)
+	}
+}
+
+// Record adds the result to every window containing the given time
+func (leaderboard *Leaderboard) Record(result GameResult, at time.Time) error {
+	leaderboard.Lock()
+	defer leaderboard.Unlock()
+
+	for _, window := range []LeaderboardWindow{LeaderboardAllTime, LeaderboardWeekly, LeaderboardDaily} {
+		key := bucketKey(window, at)
+		bucket, exists := leaderboard.Buckets[key]
+		if !exists {
+			bucket = make(map[string]*LeaderboardEntry)
+			leaderboard.Buckets[key] = bucket
+		}
+
+		entry, exists := bucket[result.PlayerID]
+		if !exists {
+			entry = &LeaderboardEntry{PlayerID: result.PlayerID}
+			bucket[result.PlayerID] = entry
+		}
+
+		// Players may change their name between games, show the latest one
+		entry.Name = result.Name
+		entry.GamesPlayed++
+		entry.Correct += result.Correct
+		entry.Answered += result.Answered
+		entry.TotalScore += result.Score
+		if result.Score > entry.BestScore {
+			entry.BestScore = result.Score
+		}
+	}
+	leaderboard.removeEndedBuckets(at)
+
+	if leaderboard.path == "" {
+		return nil
+	}
+	return utils.WriteJSONFile(leaderboard.path, leaderboard)
+}
+
+// removeEndedBuckets drops the daily and weekly buckets of windows that ended before the one containing the given time, they are never ranked again.
+// Bucket keys of one window sort in time order, so older buckets sort before the current one
+func (leaderboard *Leaderboard) removeEndedBuckets(at time.Time) {
+	for key := range leaderboard.Buckets {
+		window, _, found := strings.Cut(key, ":")
+		if found && key < bucketKey(LeaderboardWindow(window), at) {
+			delete(leaderboard.Buckets, key)
+		}
+	}
+}
+
+// Ranked returns the entries of the window containing the given time, best first, with the rating of every player
+func (leaderboard *Leaderboard) Ranked(window LeaderboardWindow, sortBy LeaderboardSort, at time.Time, ratings *RatingStore) []LeaderboardEntry {
+	leaderboard.Lock()
+	bucket := leaderboard.Buckets[bucketKey(window, at)]
+	entries := make([]LeaderboardEntry, 0, len(bucket))
+	for _, entry := range bucket {
+		ranked := *entry
+		ranked.Accuracy = accuracyPercent(ranked.Correct, ranked.Answered)
+		entries = append(entries, ranked)
+	}
+	leaderboard.Unlock()
+
+	for i := range entries {
+		entries[i].Rating = ratings.RatingOf(entries[i].PlayerID)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch sortBy {
+		case SortByAccuracy:
+			if a.Accuracy != b.Accuracy {
+				return a.Accuracy > b.Accuracy
+			}
+		case SortByBestScore:
+			if a.BestScore != b.BestScore {
+				return a.BestScore > b.BestScore
+			}
+		case SortByRating:
+			if a.Rating != b.Rating {
+				return a.Rating > b.Rating
+			}
+		}
+		if a.TotalScore != b.TotalScore {
+			return a.TotalScore > b.TotalScore
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.PlayerID < b.PlayerID
+	})
+
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// LobbyRoomID is the hub room that clients browsing the public game list subscribe to
+const LobbyRoomID = "lobbies"
+
+// Lobby is the public listing of a multiplayer game that can still be joined
+type Lobby struct {
+	ID        string `json:"id"`
+	Players   int    `json:"players"`
+	Capacity  int    `json:"capacity"`
+	Questions int    `json:"questions"`
+	Category  string `json:"category"`
+	// Seconds until the game starts, nil until the owner starts the countdown
+	StartsIn *int `json:"startsIn"`
+}
+
+// LobbyFilter narrows down the lobbies returned by ListLobbies, zero values match everything
+type LobbyFilter struct {
+	Category  string
+	Questions int
+}
+
+// IsJoinable returns true if the game is a public multiplayer game that has not started and has room left
+func (gameServer *GameServer) IsJoinable() bool {
+	return gameServer.Multiplayer &&
+		gameServer.Settings.Public &&
+		gameServer.Started.IsZero() &&
+		gameServer.Finished.IsZero() &&
+		!gameServer.IsFull()
+}
+
+func newLobby(gameServer *GameServer) Lobby {
+	lobby := Lobby{
+		ID:        gameServer.ID,
+		Players:   gameServer.Sessions.Count(),
+		Capacity:  gameServer.Settings.Capacity,
+		Questions: len(gameServer.Questions),
+		Category:  gameServer.Settings.Category,
+	}
+
+	if !gameServer.StartsAt.IsZero() {
+		startsIn := int(time.Until(gameServer.StartsAt).Seconds())
+		if startsIn < 0 {
+			startsIn = 0
+		}
+		lobby.StartsIn = &startsIn
+	}
+
+	return lobby
+}
+
+// ListLobbies returns all joinable games matching the filter, oldest first
+func ListLobbies(filter LobbyFilter) []Lobby {
+	GameServersLock.RLock()
+	gameServers := make([]*GameServer, 0)
+	for _, gameServer := range GameServers {
+		if !gameServer.IsJoinable() {
+			continue
+		}
+		if filter.Category != "" && gameServer.Settings.Category != filter.Category {
+			continue
+		}
+		if filter.Questions > 0 && len(gameServer.Questions) != filter.Questions {
+			continue
+		}
+		gameServers = append(gameServers, gameServer)
+	}
+	GameServersLock.RUnlock()
+
+	sort.Slice(gameServers, func(i, j int) bool {
+		return gameServers[i].Created.Before(gameServers[j].Created)
+	})
+
+	lobbies := make([]Lobby, 0, len(gameServers))
+	for _, gameServer := range gameServers {
+		lobbies = append(lobbies, newLobby(gameServer))
+	}
+	return lobbies
+}
+
+// NewLobbyUpdateMessage builds the message sent to lobby subscribers with the current list of joinable games
+func NewLobbyUpdateMessage() Message {
+	contentValue, _ := json.Marshal(ListLobbies(LobbyFilter{}))
+	return Message{Type: "lobbyUpdate", ID: LobbyRoomID, Content: string(contentValue)}
+}
@@ -0,0 +1,300 @@
+package models
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/utils"
+)
+
+const (
+	// Players needed before a match is made once the wait timeout has passed
+	MatchMinPlayers = 2
+	// A match is made right away once this many players are waiting
+	MatchMaxPlayers = 4
+	// How long the first player in a queue waits for it to fill up
+	MatchWaitTimeout = 15 * time.Second
+	// Players are only matched with others whose rating falls in the same band
+	RatingBandWidth = 200
+	// How long matched and failed tickets are kept so clients can still look them up
+	matchedTicketTTL = 10 * time.Minute
+	// How long a player waits in a queue that never fills up before their ticket expires
+	WaitingTicketTTL = 5 * time.Minute
+)
+
+var (
+	ErrTicketNotFound = errors.New("matchmaking ticket not found")
+	ErrTicketExpired  = errors.New("no match was found before the ticket expired")
+	ErrTicketReplaced = errors.New("the player joined the queue again with another ticket")
+)
+
+// MatchPreferences identifies a queue, only players with the same preferences are matched together
+type MatchPreferences struct {
+	Questions  int
+	Category   string
+	RatingBand int
+}
+
+// RatingBand returns the band a rating falls into
+func RatingBand(rating float64) int {
+	return int(rating) / RatingBandWidth
+}
+
+type Ticket struct {
+	ID          string
+	PlayerID    string
+	Name        string
+	Preferences MatchPreferences
+	Enqueued    time.Time
+	// Last position the player was told about
+	Position int
+	// Set once the player has been moved into a game
+	GameID    string
+	SessionID string
+	Matched   time.Time
+	// Set when no game could be made for the ticket, the player has to enqueue again
+	Failure error
+	Failed  time.Time
+}
+
+// MatchmakingQueue holds the players waiting for a quick match, grouped by their preferences
+type MatchmakingQueue struct {
+	sync.Mutex
+	Queues  map[MatchPreferences][]*Ticket
+	Tickets map[string]*Ticket
+}
+
+// Matchmaking is the global quick-match queue
+var Matchmaking = NewMatchmakingQueue()
+
+func NewMatchmakingQueue() *MatchmakingQueue {
+	return &MatchmakingQueue{
+		Queues:  make(map[MatchPreferences][]*Ticket),
+		Tickets: make(map[string]*Ticket),
+	}
+}
+
+// Enqueue adds a player to the queue matching their preferences and returns their ticket.
+// A player waits with one ticket at a time, the tickets they were already waiting with are returned failed with ErrTicketReplaced.
+func (queue *MatchmakingQueue) Enqueue(playerID string, name string, preferences MatchPreferences, now time.Time) (Ticket, []Ticket) {
+	queue.Lock()
+	defer queue.Unlock()
+
+	replaced := make([]Ticket, 0)
+	for queuePreferences, waiting := range queue.Queues {
+		remaining := waiting[:0]
+		for _, ticket := range waiting {
+			if ticket.PlayerID != playerID {
+				remaining = append(remaining, ticket)
+				continue
+			}
+			ticket.Failure = ErrTicketReplaced
+			ticket.Failed = now
+			replaced = append(replaced, *ticket)
+		}
+
+		if len(remaining) == 0 {
+			delete(queue.Queues, queuePreferences)
+			continue
+		}
+		queue.Queues[queuePreferences] = remaining
+	}
+
+	ticket := &Ticket{
+		ID:          utils.GenerateRandomID(),
+		PlayerID:    playerID,
+		Name:        name,
+		Preferences: preferences,
+		Enqueued:    now,
+	}
+	queue.Queues[preferences] = append(queue.Queues[preferences], ticket)
+	ticket.Position = len(queue.Queues[preferences])
+	queue.Tickets[ticket.ID] = ticket
+	return *ticket, replaced
+}
+
+// Cancel removes a waiting ticket from its queue
+func (queue *MatchmakingQueue) Cancel(ticketID string) error {
+	queue.Lock()
+	defer queue.Unlock()
+
+	ticket, exists := queue.Tickets[ticketID]
+	if !exists || ticket.GameID != "" {
+		return ErrTicketNotFound
+	}
+
+	waiting := queue.Queues[ticket.Preferences]
+	for i, waitingTicket := range waiting {
+		if waitingTicket == ticket {
+			queue.Queues[ticket.Preferences] = append(waiting[:i:i], waiting[i+1:]...)
+			break
+		}
+	}
+	delete(queue.Tickets, ticketID)
+	return nil
+}
+
+// GetTicket returns a copy of the ticket
+func (queue *MatchmakingQueue) GetTicket(ticketID string) (Ticket, bool) {
+	queue.Lock()
+	defer queue.Unlock()
+
+	ticket, exists := queue.Tickets[ticketID]
+	if !exists {
+		return Ticket{}, false
+	}
+	return *ticket, true
+}
+
+// TakeMatches removes and returns the groups of tickets that are ready to play.
+// A queue is matched as soon as it holds MatchMaxPlayers different players, or once its oldest ticket
+// has waited MatchWaitTimeout and at least MatchMinPlayers different players are waiting.
+func (queue *MatchmakingQueue) TakeMatches(now time.Time) [][]Ticket {
+	queue.Lock()
+	defer queue.Unlock()
+
+	matches := make([][]Ticket, 0)
+	for preferences, waiting := range queue.Queues {
+		for {
+			group, rest := takeDistinctPlayers(waiting, MatchMaxPlayers)
+			if len(group) < MatchMaxPlayers {
+				break
+			}
+			matches = append(matches, copyTickets(group))
+			waiting = rest
+		}
+
+		if len(waiting) > 0 && now.Sub(waiting[0].Enqueued) >= MatchWaitTimeout {
+			if group, rest := takeDistinctPlayers(waiting, MatchMaxPlayers); len(group) >= MatchMinPlayers {
+				matches = append(matches, copyTickets(group))
+				waiting = rest
+			}
+		}
+
+		if len(waiting) == 0 {
+			delete(queue.Queues, preferences)
+			continue
+		}
+		queue.Queues[preferences] = waiting
+	}
+	return matches
+}
+
+// takeDistinctPlayers splits the first tickets of up to size different players from the rest of a queue,
+// a player is never matched against themselves
+func takeDistinctPlayers(waiting []*Ticket, size int) ([]*Ticket, []*Ticket) {
+	group := make([]*Ticket, 0, size)
+	rest := make([]*Ticket, 0, len(waiting))
+	players := make(map[string]bool)
+	for _, ticket := range waiting {
+		if len(group) < size && !players[ticket.PlayerID] {
+			players[ticket.PlayerID] = true
+			group = append(group, ticket)
+			continue
+		}
+		rest = append(rest, ticket)
+	}
+	return group, rest
+}
+
+func copyTickets(tickets []*Ticket) []Ticket {
+	copied := make([]Ticket, len(tickets))
+	for i, ticket := range tickets {
+		copied[i] = *ticket
+	}
+	return copied
+}
+
+// MarkMatched records the game and session a ticket was moved into
+func (queue *MatchmakingQueue) MarkMatched(ticketID string, gameID string, sessionID string, now time.Time) {
+	queue.Lock()
+	defer queue.Unlock()
+
+	if ticket, exists := queue.Tickets[ticketID]; exists {
+		ticket.GameID = gameID
+		ticket.SessionID = sessionID
+		ticket.Matched = now
+	}
+}
+
+// MarkFailed records that no game could be made for a ticket that was taken from its queue
+func (queue *MatchmakingQueue) MarkFailed(ticketID string, failure error, now time.Time) {
+	queue.Lock()
+	defer queue.Unlock()
+
+	if ticket, exists := queue.Tickets[ticketID]; exists {
+		ticket.Failure = failure
+		ticket.Failed = now
+	}
+}
+
+// Requeue puts tickets that were taken from their queue back at its front, they keep their place in line
+func (queue *MatchmakingQueue) Requeue(tickets []Ticket) {
+	queue.Lock()
+	defer queue.Unlock()
+
+	requeued := make(map[MatchPreferences][]*Ticket)
+	for _, taken := range tickets {
+		if ticket, exists := queue.Tickets[taken.ID]; exists {
+			requeued[ticket.Preferences] = append(requeued[ticket.Preferences], ticket)
+		}
+	}
+	for preferences, front := range requeued {
+		queue.Queues[preferences] = append(front, queue.Queues[preferences]...)
+	}
+}
+
+// UpdatePositions returns the waiting tickets whose position in their queue has changed
+func (queue *MatchmakingQueue) UpdatePositions() []Ticket {
+	queue.Lock()
+	defer queue.Unlock()
+
+	changed := make([]Ticket, 0)
+	for _, waiting := range queue.Queues {
+		for i, ticket := range waiting {
+			if ticket.Position != i+1 {
+				ticket.Position = i + 1
+				changed = append(changed, *ticket)
+			}
+		}
+	}
+	return changed
+}
+
+// RemoveExpired forgets matched and failed tickets older than matchedTicketTTL and takes tickets that waited
+// longer than WaitingTicketTTL out of their queue. The waiting tickets that expired are returned with ErrTicketExpired.
+func (queue *MatchmakingQueue) RemoveExpired(now time.Time) []Ticket {
+	queue.Lock()
+	defer queue.Unlock()
+
+	for ticketID, ticket := range queue.Tickets {
+		matchedExpired := ticket.GameID != "" && now.Sub(ticket.Matched) > matchedTicketTTL
+		failedExpired := ticket.Failure != nil && now.Sub(ticket.Failed) > matchedTicketTTL
+		if matchedExpired || failedExpired {
+			delete(queue.Tickets, ticketID)
+		}
+	}
+
+	expired := make([]Ticket, 0)
+	for preferences, waiting := range queue.Queues {
+		remaining := waiting[:0]
+		for _, ticket := range waiting {
+			if now.Sub(ticket.Enqueued) <= WaitingTicketTTL {
+				remaining = append(remaining, ticket)
+				continue
+			}
+			ticket.Failure = ErrTicketExpired
+			ticket.Failed = now
+			expired = append(expired, *ticket)
+			delete(queue.Tickets, ticket.ID)
+		}
+
+		if len(remaining) == 0 {
+			delete(queue.Queues, preferences)
+			continue
+		}
+		queue.Queues[preferences] = remaining
+	}
+	return expired
+}
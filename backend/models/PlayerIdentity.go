@@ -0,0 +1,85 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"sync"
+
+	"github.com/ProlificLabs/captrivia/utils"
+)
+
+// PlayerIdentityStore issues player IDs and signs them, so a client can only present an ID the server gave it
+type PlayerIdentityStore struct {
+	sync.Mutex
+	path   string
+	Secret []byte `json:"secret"`
+}
+
+// PlayerIdentities signs the identity of every player
+var PlayerIdentities = NewPlayerIdentityStore()
+
+// NewPlayerIdentityStore creates a store with a new secret, identities it issues only last until the secret is replaced
+func NewPlayerIdentityStore() *PlayerIdentityStore {
+	return &PlayerIdentityStore{Secret: newIdentitySecret()}
+}
+
+// Load reads the secret saved at path, a new one is saved when there is none
+func (store *PlayerIdentityStore) Load(path string) error {
+	store.Lock()
+	defer store.Unlock()
+
+	store.path = path
+	store.Secret = nil
+	if err := utils.ReadJSONFile(path, store); err != nil {
+		return err
+	}
+	if len(store.Secret) > 0 {
+		return nil
+	}
+
+	store.Secret = newIdentitySecret()
+	return utils.WriteJSONFile(path, store)
+}
+
+// Issue creates a new player ID and the token that proves it
+func (store *PlayerIdentityStore) Issue() (string, string) {
+	playerID := utils.GenerateRandomID()
+	return playerID, store.Token(playerID)
+}
+
+// Token returns the signed form of the player ID
+func (store *PlayerIdentityStore) Token(playerID string) string {
+	return playerID + "." + base64.RawURLEncoding.EncodeToString(store.sign(playerID))
+}
+
+// Verify returns the player ID of a token, false when the token was not issued by this store
+func (store *PlayerIdentityStore) Verify(token string) (string, bool) {
+	playerID, signature, found := strings.Cut(token, ".")
+	if !found || playerID == "" {
+		return "", false
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(decoded, store.sign(playerID)) {
+		return "", false
+	}
+	return playerID, true
+}
+
+func (store *PlayerIdentityStore) sign(playerID string) []byte {
+	store.Lock()
+	defer store.Unlock()
+
+	mac := hmac.New(sha256.New, store.Secret)
+	mac.Write([]byte(playerID))
+	return mac.Sum(nil)
+}
+
+func newIdentitySecret() []byte {
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	return secret
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MinPlayerNameLength = 1
+	MaxPlayerNameLength = 24
+)
+
+// Letters, numbers, spaces and a few punctuation characters commonly found in names
+var playerNamePattern = regexp.MustCompile(`^[\p{L}\p{N} _.'-]+$`)
+
+var (
+	nameDenyList     []string
+	nameDenyListLock sync.RWMutex
+)
+
+// SetNameDenyList replaces the words that are not allowed to appear in player names
+func SetNameDenyList(words []string) {
+	denied := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" {
+			denied = append(denied, word)
+		}
+	}
+
+	nameDenyListLock.Lock()
+	defer nameDenyListLock.Unlock()
+	nameDenyList = denied
+}
+
+// LoadNameDenyList reads the deny-list from a file with one word per line, a missing file leaves the list empty
+func LoadNameDenyList(path string) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	SetNameDenyList(words)
+	return nil
+}
+
+var (
+	ErrNameRequired          = errors.New("name is required")
+	ErrNameTooLong           = errors.New("name is too long")
+	ErrNameInvalidCharacters = errors.New("name contains invalid characters")
+	ErrNameNotAllowed        = errors.New("name is not allowed")
+)
+
+// ValidatePlayerName checks the name against the length limits, allowed characters and the deny-list.
+// Denied words only match whole words of the name, so "Badminton" is not rejected for "admin".
+func ValidatePlayerName(name string) error {
+	name = strings.TrimSpace(name)
+	length := utf8.RuneCountInString(name)
+	if length < MinPlayerNameLength {
+		return ErrNameRequired
+	}
+	if length > MaxPlayerNameLength {
+		return ErrNameTooLong
+	}
+	if !playerNamePattern.MatchString(name) {
+		return ErrNameInvalidCharacters
+	}
+
+	nameDenyListLock.RLock()
+	defer nameDenyListLock.RUnlock()
+
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		for _, denied := range nameDenyList {
+			if word == denied {
+				return ErrNameNotAllowed
+			}
+		}
+	}
+	return nil
+}
@@ -1,17 +1,63 @@
 package models
 
 import (
+	"encoding/json"
+	"sort"
 	"time"
 )
 
+// AnswerRecord is a single answer submitted by a session
+type AnswerRecord struct {
+	QuestionID string `json:"questionId"`
+	// Shape depends on the question type
+	Answer  json.RawMessage `json:"answer"`
+	Correct bool            `json:"correct"`
+	// True if the answer earned points, in multiplayer only the first correct answer does
+	Scored     bool      `json:"scored"`
+	AnsweredAt time.Time `json:"answeredAt"`
+}
+
 type PlayerSession struct {
 	ID string
-	Name string
-	Score int
+	// Stable identity of the player across games
+	PlayerID string
+	Name     string
+	// Locale the questions are shown in
+	Locale string
+	Score  int
+	// Number of questions answered correctly, whether or not they scored
+	Correct         int
 	CurrentQuestion int
-	Finished time.Time
+	Answers         []AnswerRecord
+	Finished        time.Time
+	// Uses of each power-up so far
+	PowerUpsUsed map[PowerUp]int
+	// Set by double points until the next answer that scores
+	DoublePoints bool
+	// Options hidden by 50/50, keyed by question ID
+	RemovedOptions map[string][]int
+	// IDs of the questions skipped with the skip power-up
+	Skipped []string
+}
+
+func (ps *PlayerSession) MarkFinished(now time.Time) {
+	ps.Finished = now
 }
 
-func (ps *PlayerSession) MarkFinished() {
-	ps.Finished = time.Now()
-}
\ No newline at end of file
+// RankSessions sorts the sessions by score, best first, and returns the rank of each one.
+// Sessions with equal scores share the better rank.
+func RankSessions(sessions []*PlayerSession) ([]*PlayerSession, []int) {
+	ranked := append([]*PlayerSession{}, sessions...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+
+	ranks := make([]int, len(ranked))
+	for i, session := range ranked {
+		ranks[i] = i + 1
+		if i > 0 && ranked[i-1].Score == session.Score {
+			ranks[i] = ranks[i-1]
+		}
+	}
+	return ranked, ranks
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+)
+
+type PowerUp string
+
+const (
+	// Removes two wrong options of the current question for the session
+	PowerUpFiftyFifty PowerUp = "fiftyFifty"
+	// Moves on to the next question without answering the current one
+	PowerUpSkip PowerUp = "skip"
+	// The next correct answer that scores is worth twice the points
+	PowerUpDoublePoints PowerUp = "doublePoints"
+)
+
+func (powerUp PowerUp) IsValid() bool {
+	return powerUp == PowerUpFiftyFifty || powerUp == PowerUpSkip || powerUp == PowerUpDoublePoints
+}
+
+// Uses of every power-up per session when the game does not choose
+const DefaultPowerUpUses = 1
+
+var (
+	ErrPowerUpUsedUp         = errors.New("no uses of this power-up left")
+	ErrDoublePointsActive    = errors.New("double points is already active")
+	ErrNotEnoughWrongOptions = errors.New("the question does not have enough wrong options")
+	ErrFiftyFiftyUnsupported = errors.New("50/50 only works on single choice questions")
+)
+
+// DefaultPowerUps gives every session DefaultPowerUpUses of each power-up
+func DefaultPowerUps() map[PowerUp]int {
+	return map[PowerUp]int{
+		PowerUpFiftyFifty:   DefaultPowerUpUses,
+		PowerUpSkip:         DefaultPowerUpUses,
+		PowerUpDoublePoints: DefaultPowerUpUses,
+	}
+}
+
+// PowerUpsLeft returns how many more times the session can use each power-up of the game
+func (ps *PlayerSession) PowerUpsLeft(limits map[PowerUp]int) map[PowerUp]int {
+	left := make(map[PowerUp]int, len(limits))
+	for powerUp, limit := range limits {
+		left[powerUp] = limit - ps.PowerUpsUsed[powerUp]
+	}
+	return left
+}
+
+// UsePowerUp uses up one use of the power-up if the session has any left
+func (ps *PlayerSession) UsePowerUp(powerUp PowerUp, limits map[PowerUp]int) error {
+	if ps.PowerUpsUsed[powerUp] >= limits[powerUp] {
+		return ErrPowerUpUsedUp
+	}
+	if powerUp == PowerUpDoublePoints && ps.DoublePoints {
+		return ErrDoublePointsActive
+	}
+
+	if ps.PowerUpsUsed == nil {
+		ps.PowerUpsUsed = make(map[PowerUp]int)
+	}
+	ps.PowerUpsUsed[powerUp]++
+	return nil
+}
+
+// FiftyFiftyOptions picks two wrong options of the question to remove
+func FiftyFiftyOptions(question Question, random *rand.Rand) ([]int, error) {
+	if question.Kind() != QuestionSingleChoice {
+		return nil, ErrFiftyFiftyUnsupported
+	}
+
+	wrong := make([]int, 0, len(question.Options))
+	for i := range question.Options {
+		if i != question.CorrectIndex {
+			wrong = append(wrong, i)
+		}
+	}
+	if len(wrong) < 2 {
+		return nil, ErrNotEnoughWrongOptions
+	}
+
+	random.Shuffle(len(wrong), func(i, j int) { wrong[i], wrong[j] = wrong[j], wrong[i] })
+	return wrong[:2], nil
+}
+
+// RemoveOptions hides options of the question from the session
+func (ps *PlayerSession) RemoveOptions(questionID string, options []int) {
+	if ps.RemovedOptions == nil {
+		ps.RemovedOptions = make(map[string][]int)
+	}
+	ps.RemovedOptions[questionID] = options
+}
+
+// IsOptionRemoved returns true if the answer picks an option of the question that a 50/50 hid from the session
+func (ps *PlayerSession) IsOptionRemoved(questionID string, answer json.RawMessage) bool {
+	var option int
+	if json.Unmarshal(answer, &option) != nil {
+		return false
+	}
+	for _, removed := range ps.RemovedOptions[questionID] {
+		if removed == option {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSkipped returns true if the session skipped the question
+func (ps *PlayerSession) IsSkipped(questionID string) bool {
+	for _, skipped := range ps.Skipped {
+		if skipped == questionID {
+			return true
+		}
+	}
+	return false
+}
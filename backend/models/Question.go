@@ -2,8 +2,27 @@ package models
 
 type Question struct {
 	ID           		string   `json:"id"`
+	Category     		string   `json:"category"`
 	QuestionText 		string   `json:"questionText"`
 	Options      		[]string `json:"options"`
 	CorrectIndex 		int      `json:"correctIndex"`
+	// Type decides how answers are graded, questions without one are single choice
+	Type         		QuestionType `json:"type,omitempty"`
+	// Options that must all be picked in a multi-select question
+	CorrectIndexes 	[]int    `json:"correctIndexes,omitempty"`
+	// Multi-select answers that are partly right earn part of the points
+	PartialCredit 	bool     `json:"partialCredit,omitempty"`
+	// Whether the statement of a true/false question is true
+	IsTrue       		bool     `json:"isTrue,omitempty"`
+	// Answer of a numeric question and how far off an answer can be
+	CorrectNumber 	float64  `json:"correctNumber,omitempty"`
+	Tolerance    		float64  `json:"tolerance,omitempty"`
+	// Option indexes of an ordering question in their correct order
+	CorrectOrder 		[]int    `json:"correctOrder,omitempty"`
+	Explanation  		string   `json:"explanation"`
+	// Seed a generated question was built from, zero for questions from the bank
+	Seed         		int64    `json:"seed,omitempty"`
+	// Text of the question in other locales, keyed by locale
+	Translations 		map[string]QuestionTranslation `json:"translations,omitempty"`
 	CorrectSession 	string      `json:"correctSession"`
 }
\ No newline at end of file
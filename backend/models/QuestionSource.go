@@ -0,0 +1,8 @@
+package models
+
+// QuestionSource provides the questions games are built from
+type QuestionSource interface {
+	// Questions returns the questions of the category, or all of them without a category.
+	// Sources without questions for the category return none and no error.
+	Questions(category string) ([]Question, error)
+}
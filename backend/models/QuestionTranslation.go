@@ -0,0 +1,44 @@
+package models
+
+import "github.com/ProlificLabs/captrivia/i18n"
+
+// QuestionTranslation is the text of a question in one locale, fields left empty keep the default text
+type QuestionTranslation struct {
+	QuestionText string   `json:"questionText"`
+	Options      []string `json:"options"`
+	Explanation  string   `json:"explanation"`
+}
+
+// Localize returns the question with its text in the locale. Each field falls back to
+// the language without its region and then to the default text.
+func (question Question) Localize(locale string) Question {
+	localized := question
+	questionText, options, explanation := false, false, false
+
+	for _, candidate := range i18n.Fallbacks(locale) {
+		translation, exists := question.Translations[candidate]
+		if !exists {
+			continue
+		}
+		if !questionText && translation.QuestionText != "" {
+			localized.QuestionText, questionText = translation.QuestionText, true
+		}
+		// Options are matched by index, so a translation must cover all of them
+		if !options && len(translation.Options) == len(question.Options) && len(translation.Options) > 0 {
+			localized.Options, options = translation.Options, true
+		}
+		if !explanation && translation.Explanation != "" {
+			localized.Explanation, explanation = translation.Explanation, true
+		}
+	}
+	return localized
+}
+
+// LocalizeQuestions returns the questions with their text in the locale
+func LocalizeQuestions(questions []Question, locale string) []Question {
+	localized := make([]Question, len(questions))
+	for i, question := range questions {
+		localized[i] = question.Localize(locale)
+	}
+	return localized
+}
@@ -0,0 +1,266 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+type QuestionType string
+
+const (
+	// One option is correct, the answer is its index
+	QuestionSingleChoice QuestionType = "singleChoice"
+	// The answer is true or false
+	QuestionTrueFalse QuestionType = "trueFalse"
+	// Several options are correct, the answer is the list of picked indexes
+	QuestionMultiSelect QuestionType = "multiSelect"
+	// The answer is a number
+	QuestionNumeric QuestionType = "numeric"
+	// The answer is every option index in order
+	QuestionOrdering QuestionType = "ordering"
+)
+
+var (
+	ErrQuestionNotFound = errors.New("question not found")
+	ErrInvalidAnswer    = errors.New("answer does not fit the question type")
+)
+
+// Numeric answers within this distance of the tolerance still count, so rounding does not cost points
+const numericEpsilon = 1e-9
+
+// Grader checks the answers to one type of question
+type Grader interface {
+	// Validate checks that the question has everything needed to grade it
+	Validate(question Question) error
+	// Grade returns the credit the answer earns, from 0 when wrong to 1 when fully correct
+	Grade(question Question, answer json.RawMessage) (float64, error)
+	// Solution returns the correct answer in the same shape as an answer
+	Solution(question Question) interface{}
+}
+
+var graders = map[QuestionType]Grader{
+	QuestionSingleChoice: singleChoiceGrader{},
+	QuestionTrueFalse:    trueFalseGrader{},
+	QuestionMultiSelect:  multiSelectGrader{},
+	QuestionNumeric:      numericGrader{},
+	QuestionOrdering:     orderingGrader{},
+}
+
+// Kind returns the type of the question, questions without one are single choice
+func (question Question) Kind() QuestionType {
+	if question.Type == "" {
+		return QuestionSingleChoice
+	}
+	return question.Type
+}
+
+func (question Question) grader() (Grader, error) {
+	grader, exists := graders[question.Kind()]
+	if !exists {
+		return nil, fmt.Errorf("question %s has unknown type %s", question.ID, question.Type)
+	}
+	return grader, nil
+}
+
+// Validate checks that the question can be graded
+func (question Question) Validate() error {
+	grader, err := question.grader()
+	if err != nil {
+		return err
+	}
+	if err := grader.Validate(question); err != nil {
+		return fmt.Errorf("question %s: %w", question.ID, err)
+	}
+	return nil
+}
+
+// Grade returns the credit an answer to the question earns, from 0 to 1
+func (question Question) Grade(answer json.RawMessage) (float64, error) {
+	grader, err := question.grader()
+	if err != nil {
+		return 0, err
+	}
+	return grader.Grade(question, answer)
+}
+
+// Solution returns the correct answer to the question
+func (question Question) Solution() interface{} {
+	grader, err := question.grader()
+	if err != nil {
+		return nil
+	}
+	return grader.Solution(question)
+}
+
+func decodeAnswer(answer json.RawMessage, value interface{}) error {
+	if len(answer) == 0 || json.Unmarshal(answer, value) != nil {
+		return ErrInvalidAnswer
+	}
+	return nil
+}
+
+// validIndexes returns true if every index points to an option and none is repeated
+func validIndexes(indexes []int, options int) bool {
+	seen := make(map[int]bool, len(indexes))
+	for _, index := range indexes {
+		if index < 0 || index >= options || seen[index] {
+			return false
+		}
+		seen[index] = true
+	}
+	return true
+}
+
+type singleChoiceGrader struct{}
+
+func (singleChoiceGrader) Validate(question Question) error {
+	if len(question.Options) < 2 || question.CorrectIndex < 0 || question.CorrectIndex >= len(question.Options) {
+		return errors.New("single choice questions need at least two options and a valid correct index")
+	}
+	return nil
+}
+
+func (singleChoiceGrader) Grade(question Question, answer json.RawMessage) (float64, error) {
+	var index int
+	if err := decodeAnswer(answer, &index); err != nil {
+		return 0, err
+	}
+	if index < 0 || index >= len(question.Options) {
+		return 0, ErrInvalidAnswer
+	}
+	if index == question.CorrectIndex {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (singleChoiceGrader) Solution(question Question) interface{} {
+	return question.CorrectIndex
+}
+
+type trueFalseGrader struct{}
+
+func (trueFalseGrader) Validate(question Question) error {
+	return nil
+}
+
+func (trueFalseGrader) Grade(question Question, answer json.RawMessage) (float64, error) {
+	var value bool
+	if err := decodeAnswer(answer, &value); err != nil {
+		return 0, err
+	}
+	if value == question.IsTrue {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (trueFalseGrader) Solution(question Question) interface{} {
+	return question.IsTrue
+}
+
+type multiSelectGrader struct{}
+
+func (multiSelectGrader) Validate(question Question) error {
+	if len(question.CorrectIndexes) == 0 || !validIndexes(question.CorrectIndexes, len(question.Options)) {
+		return errors.New("multi-select questions need at least one valid correct index")
+	}
+	return nil
+}
+
+// Grade gives full credit when exactly the correct options are picked. With partial credit every
+// correct pick earns its share of the points and every wrong pick takes a share away.
+func (multiSelectGrader) Grade(question Question, answer json.RawMessage) (float64, error) {
+	var picked []int
+	if err := decodeAnswer(answer, &picked); err != nil {
+		return 0, err
+	}
+	if !validIndexes(picked, len(question.Options)) {
+		return 0, ErrInvalidAnswer
+	}
+
+	correct := make(map[int]bool, len(question.CorrectIndexes))
+	for _, index := range question.CorrectIndexes {
+		correct[index] = true
+	}
+
+	hits, misses := 0, 0
+	for _, index := range picked {
+		if correct[index] {
+			hits++
+		} else {
+			misses++
+		}
+	}
+
+	if hits == len(correct) && misses == 0 {
+		return 1, nil
+	}
+	if !question.PartialCredit {
+		return 0, nil
+	}
+	return math.Max(0, float64(hits-misses)/float64(len(correct))), nil
+}
+
+func (multiSelectGrader) Solution(question Question) interface{} {
+	solution := append([]int{}, question.CorrectIndexes...)
+	sort.Ints(solution)
+	return solution
+}
+
+type numericGrader struct{}
+
+func (numericGrader) Validate(question Question) error {
+	if question.Tolerance < 0 {
+		return errors.New("numeric questions cannot have a negative tolerance")
+	}
+	return nil
+}
+
+func (numericGrader) Grade(question Question, answer json.RawMessage) (float64, error) {
+	var value float64
+	if err := decodeAnswer(answer, &value); err != nil {
+		return 0, err
+	}
+	if math.Abs(value-question.CorrectNumber) <= question.Tolerance+numericEpsilon {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (numericGrader) Solution(question Question) interface{} {
+	return question.CorrectNumber
+}
+
+type orderingGrader struct{}
+
+func (orderingGrader) Validate(question Question) error {
+	if len(question.Options) < 2 || len(question.CorrectOrder) != len(question.Options) || !validIndexes(question.CorrectOrder, len(question.Options)) {
+		return errors.New("ordering questions need a correct order that uses every option once")
+	}
+	return nil
+}
+
+func (orderingGrader) Grade(question Question, answer json.RawMessage) (float64, error) {
+	var order []int
+	if err := decodeAnswer(answer, &order); err != nil {
+		return 0, err
+	}
+	if len(order) != len(question.Options) || !validIndexes(order, len(question.Options)) {
+		return 0, ErrInvalidAnswer
+	}
+
+	for i, index := range order {
+		if question.CorrectOrder[i] != index {
+			return 0, nil
+		}
+	}
+	return 1, nil
+}
+
+func (orderingGrader) Solution(question Question) interface{} {
+	return question.CorrectOrder
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// NewRandom returns a random number generator that is safe for concurrent use, the same seed
+// always gives the same numbers
+func NewRandom(seed int64) *rand.Rand {
+	return rand.New(&lockedSource{source: rand.NewSource(seed).(rand.Source64)})
+}
+
+type lockedSource struct {
+	lock   sync.Mutex
+	source rand.Source64
+}
+
+func (source *lockedSource) Int63() int64 {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+
+	return source.source.Int63()
+}
+
+func (source *lockedSource) Uint64() uint64 {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+
+	return source.source.Uint64()
+}
+
+func (source *lockedSource) Seed(seed int64) {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+
+	source.source.Seed(seed)
+}
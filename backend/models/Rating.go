@@ -0,0 +1,151 @@
+package models
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/utils"
+)
+
+const (
+	// Rating given to players before their first rated game
+	InitialRating = 1500.0
+	// Maximum rating change from a single game
+	ratingKFactor = 32.0
+)
+
+// RatingChange is the effect of one finished game on a player's rating
+type RatingChange struct {
+	GameID  string    `json:"gameId"`
+	Before  float64   `json:"before"`
+	After   float64   `json:"after"`
+	Rank    int       `json:"rank"`
+	Players int       `json:"players"`
+	At      time.Time `json:"at"`
+}
+
+type PlayerRating struct {
+	PlayerID    string         `json:"playerId"`
+	Name        string         `json:"name"`
+	Rating      float64        `json:"rating"`
+	GamesPlayed int            `json:"gamesPlayed"`
+	History     []RatingChange `json:"history"`
+}
+
+// RatingStore keeps the skill rating of every player and saves it to disk after every change
+type RatingStore struct {
+	sync.Mutex
+	path    string
+	Players map[string]*PlayerRating `json:"players"`
+}
+
+// Ratings is the global rating store shared by all games
+var Ratings = NewRatingStore()
+
+func NewRatingStore() *RatingStore {
+	return &RatingStore{Players: make(map[string]*PlayerRating)}
+}
+
+// Load reads the saved ratings from path and keeps saving to it from then on
+func (store *RatingStore) Load(path string) error {
+	store.Lock()
+	defer store.Unlock()
+
+	store.path = path
+	return utils.ReadJSONFile(path, store)
+}
+
+// Get returns a copy of the player's rating, unrated players get the initial rating
+func (store *RatingStore) Get(playerID string) PlayerRating {
+	store.Lock()
+	defer store.Unlock()
+
+	rating, exists := store.Players[playerID]
+	if !exists {
+		return PlayerRating{PlayerID: playerID, Rating: InitialRating, History: []RatingChange{}}
+	}
+
+	copied := *rating
+	copied.History = append([]RatingChange{}, rating.History...)
+	return copied
+}
+
+// RatingOf returns only the current rating value of the player
+func (store *RatingStore) RatingOf(playerID string) float64 {
+	store.Lock()
+	defer store.Unlock()
+
+	if rating, exists := store.Players[playerID]; exists {
+		return rating.Rating
+	}
+	return InitialRating
+}
+
+// expectedScore is the Elo probability of a player rated a beating a player rated b
+func expectedScore(a float64, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// RecordGame updates the ratings of every player from the final scores of a multiplayer game.
+// Each player is treated as having played a match against every other player, winning when
+// they scored higher and drawing on equal scores, and the changes are scaled by the number of opponents.
+func (store *RatingStore) RecordGame(gameID string, sessions []*PlayerSession, at time.Time) error {
+	if len(sessions) < 2 {
+		return nil
+	}
+
+	store.Lock()
+	defer store.Unlock()
+
+	ranked, ranks := RankSessions(sessions)
+
+	before := make([]float64, len(ranked))
+	for i, session := range ranked {
+		before[i] = InitialRating
+		if rating, exists := store.Players[session.PlayerID]; exists {
+			before[i] = rating.Rating
+		}
+	}
+
+	opponents := float64(len(ranked) - 1)
+	for i, session := range ranked {
+		delta := 0.0
+		for j, opponent := range ranked {
+			if i == j {
+				continue
+			}
+
+			actual := 0.5
+			if session.Score > opponent.Score {
+				actual = 1
+			} else if session.Score < opponent.Score {
+				actual = 0
+			}
+			delta += actual - expectedScore(before[i], before[j])
+		}
+
+		rating, exists := store.Players[session.PlayerID]
+		if !exists {
+			rating = &PlayerRating{PlayerID: session.PlayerID, Rating: InitialRating}
+			store.Players[session.PlayerID] = rating
+		}
+
+		rating.Name = session.Name
+		rating.Rating = math.Round((before[i]+ratingKFactor*delta/opponents)*10) / 10
+		rating.GamesPlayed++
+		rating.History = append(rating.History, RatingChange{
+			GameID:  gameID,
+			Before:  before[i],
+			After:   rating.Rating,
+			Rank:    ranks[i],
+			Players: len(ranked),
+			At:      at,
+		})
+	}
+
+	if store.path == "" {
+		return nil
+	}
+	return utils.WriteJSONFile(store.path, store)
+}
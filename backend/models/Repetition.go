@@ -0,0 +1,161 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/utils"
+)
+
+const (
+	initialEaseFactor = 2.5
+	minimumEaseFactor = 1.3
+	// SM-2 answer quality, from 0 (blackout) to 5 (perfect recall). Answers are only right or wrong
+	// so a correct answer counts as a recall with some hesitation and a wrong one as a failed recall
+	correctAnswerQuality   = 4
+	incorrectAnswerQuality = 1
+)
+
+// RepetitionCard is the SM-2 schedule of one question for one player
+type RepetitionCard struct {
+	QuestionID  string  `json:"questionId"`
+	EaseFactor  float64 `json:"easeFactor"`
+	Repetitions int     `json:"repetitions"`
+	// Days until the question is due again
+	Interval     int       `json:"interval"`
+	Due          time.Time `json:"due"`
+	LastReviewed time.Time `json:"lastReviewed"`
+}
+
+// review updates the card with the quality of an answer following the SM-2 algorithm
+func (card *RepetitionCard) review(quality int, at time.Time) {
+	if quality >= 3 {
+		switch card.Repetitions {
+		case 0:
+			card.Interval = 1
+		case 1:
+			card.Interval = 6
+		default:
+			card.Interval = int(math.Round(float64(card.Interval) * card.EaseFactor))
+		}
+		card.Repetitions++
+	} else {
+		// Missed questions start over and come back the next day
+		card.Repetitions = 0
+		card.Interval = 1
+	}
+
+	missing := float64(5 - quality)
+	card.EaseFactor += 0.1 - missing*(0.08+missing*0.02)
+	if card.EaseFactor < minimumEaseFactor {
+		card.EaseFactor = minimumEaseFactor
+	}
+
+	card.LastReviewed = at
+	card.Due = at.AddDate(0, 0, card.Interval)
+}
+
+// RepetitionStore keeps the review schedule of every player, it is saved to disk when Save is called
+type RepetitionStore struct {
+	sync.Mutex
+	path string
+	// Cards keyed by player ID and then by question ID
+	Players map[string]map[string]*RepetitionCard `json:"players"`
+}
+
+// Repetitions is the global spaced-repetition schedule
+var Repetitions = NewRepetitionStore()
+
+func NewRepetitionStore() *RepetitionStore {
+	return &RepetitionStore{Players: make(map[string]map[string]*RepetitionCard)}
+}
+
+// Load reads the saved schedules from path and keeps saving to it from then on
+func (store *RepetitionStore) Load(path string) error {
+	store.Lock()
+	defer store.Unlock()
+
+	store.path = path
+	return utils.ReadJSONFile(path, store)
+}
+
+// Review records an answer of the player and schedules the next time the question is due, it is only kept in memory until the next Save
+func (store *RepetitionStore) Review(playerID string, questionID string, correct bool, at time.Time) {
+	store.Lock()
+	defer store.Unlock()
+
+	cards, exists := store.Players[playerID]
+	if !exists {
+		cards = make(map[string]*RepetitionCard)
+		store.Players[playerID] = cards
+	}
+
+	card, exists := cards[questionID]
+	if !exists {
+		card = &RepetitionCard{QuestionID: questionID, EaseFactor: initialEaseFactor}
+		cards[questionID] = card
+	}
+
+	quality := incorrectAnswerQuality
+	if correct {
+		quality = correctAnswerQuality
+	}
+	card.review(quality, at)
+}
+
+// Save writes every schedule to the path it was loaded from
+func (store *RepetitionStore) Save() error {
+	store.Lock()
+	defer store.Unlock()
+
+	if store.path == "" {
+		return nil
+	}
+	return utils.WriteJSONFile(store.path, store)
+}
+
+// Schedule picks up to limit questions for a practice game of the player. Questions that are
+// due come first, most overdue and hardest first, followed by questions the player has never
+// seen and then by the questions that will be due the soonest.
+func (store *RepetitionStore) Schedule(playerID string, questions []Question, limit int, now time.Time) []Question {
+	store.Lock()
+	cards := make(map[string]RepetitionCard, len(store.Players[playerID]))
+	for questionID, card := range store.Players[playerID] {
+		cards[questionID] = *card
+	}
+	store.Unlock()
+
+	due := make([]Question, 0)
+	unseen := make([]Question, 0)
+	upcoming := make([]Question, 0)
+	for _, question := range questions {
+		card, seen := cards[question.ID]
+		switch {
+		case !seen:
+			unseen = append(unseen, question)
+		case !card.Due.After(now):
+			due = append(due, question)
+		default:
+			upcoming = append(upcoming, question)
+		}
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		a, b := cards[due[i].ID], cards[due[j].ID]
+		if !a.Due.Equal(b.Due) {
+			return a.Due.Before(b.Due)
+		}
+		return a.EaseFactor < b.EaseFactor
+	})
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return cards[upcoming[i].ID].Due.Before(cards[upcoming[j].ID].Due)
+	})
+
+	scheduled := append(append(due, unseen...), upcoming...)
+	if limit > len(scheduled) {
+		limit = len(scheduled)
+	}
+	return scheduled[:limit]
+}
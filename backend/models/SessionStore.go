@@ -1,23 +1,100 @@
 package models
 
 import (
+	"errors"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/ProlificLabs/captrivia/utils"
 )
+
+var (
+	ErrPlayerBanned = errors.New("player is banned from this game")
+	ErrNameTaken    = errors.New("name is already taken in this game")
+)
+
+// NamePolicy decides what happens when a player joins with a name that is already in use
+type NamePolicy string
+
+const (
+	// Append a number to the name, e.g. "Billy Bob 2"
+	NamePolicySuffix NamePolicy = "suffix"
+	// Refuse to create the session
+	NamePolicyReject NamePolicy = "reject"
+)
+
+// IsValid returns true for the known policies, an empty policy falls back to NamePolicySuffix
+func (policy NamePolicy) IsValid() bool {
+	return policy == "" || policy == NamePolicySuffix || policy == NamePolicyReject
+}
+
 type SessionStore struct {
 	sync.Mutex
 	Sessions map[string]*PlayerSession
+	// Player IDs banned by the owner for the lifetime of the game
+	Banned map[string]bool
+	NamePolicy NamePolicy
 }
 
-func (store *SessionStore) CreateSession(name string) string {
+func NewSessionStore(namePolicy NamePolicy) *SessionStore {
+	return &SessionStore{Sessions: make(map[string]*PlayerSession), NamePolicy: namePolicy}
+}
+
+// normalizeName makes names that only differ by case or whitespace compare equal
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// uniqueName returns the name to use for a new session, the caller must hold the store lock
+func (store *SessionStore) uniqueName(name string) (string, error) {
+	taken := make(map[string]bool, len(store.Sessions))
+	for _, session := range store.Sessions {
+		taken[normalizeName(session.Name)] = true
+	}
+
+	if !taken[normalizeName(name)] {
+		return name, nil
+	}
+	if store.NamePolicy == NamePolicyReject {
+		return "", ErrNameTaken
+	}
+
+	// The suffix only uses characters a name may have, the name is shortened so the result still fits the length limit
+	for i := 2; ; i++ {
+		suffix := " " + strconv.Itoa(i)
+		base := []rune(name)
+		if limit := MaxPlayerNameLength - len(suffix); len(base) > limit {
+			base = base[:limit]
+		}
+		candidate := strings.TrimSpace(string(base)) + suffix
+		if !taken[normalizeName(candidate)] {
+			return candidate, nil
+		}
+	}
+}
+
+func (store *SessionStore) CreateSession(name string, playerID string) (string, error) {
+	if err := ValidatePlayerName(name); err != nil {
+		return "", err
+	}
+
 	store.Lock()
 	defer store.Unlock()
 
+	if store.Banned[playerID] {
+		return "", ErrPlayerBanned
+	}
+
+	name, err := store.uniqueName(strings.Join(strings.Fields(name), " "))
+	if err != nil {
+		return "", err
+	}
+
 	uniqueSessionID := utils.GenerateRandomID()
-	store.Sessions[uniqueSessionID] = &PlayerSession{ID: uniqueSessionID, Score: 0, Name: name}
+	store.Sessions[uniqueSessionID] = &PlayerSession{ID: uniqueSessionID, PlayerID: playerID, Score: 0, Name: name}
 
-	return uniqueSessionID
+	return uniqueSessionID, nil
 }
 
 func (store *SessionStore) GetSession(sessionID string) (*PlayerSession, bool) {
@@ -28,9 +105,39 @@ func (store *SessionStore) GetSession(sessionID string) (*PlayerSession, bool) {
 	return session, exists
 }
 
+// All returns the sessions of the store as a slice
+func (store *SessionStore) All() []*PlayerSession {
+	store.Lock()
+	defer store.Unlock()
+
+	sessions := make([]*PlayerSession, 0, len(store.Sessions))
+	for _, session := range store.Sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+func (store *SessionStore) Count() int {
+	store.Lock()
+	defer store.Unlock()
+
+	return len(store.Sessions)
+}
+
 func (store *SessionStore) DeleteSession(sessionID string) {
 	store.Lock()
 	defer store.Unlock()
 
 	delete(store.Sessions, sessionID)
+}
+
+// Ban prevents the player from creating new sessions in this store
+func (store *SessionStore) Ban(playerID string) {
+	store.Lock()
+	defer store.Unlock()
+
+	if store.Banned == nil {
+		store.Banned = make(map[string]bool)
+	}
+	store.Banned[playerID] = true
 }
\ No newline at end of file
@@ -0,0 +1,22 @@
+package models
+
+// StreamClient subscribes to a hub room for a streamed response, a Server-Sent Events stream or an RPC stream.
+// The handler writing the stream reads Send until the hub closes it.
+type StreamClient struct {
+	ID string
+	// Session of the player using this connection, empty for spectators
+	SessionID string
+	outbox
+}
+
+func NewStreamClient(id string, sessionID string) *StreamClient {
+	return &StreamClient{ID: id, SessionID: sessionID, outbox: newOutbox()}
+}
+
+func (c *StreamClient) Room() string {
+	return c.ID
+}
+
+func (c *StreamClient) Session() string {
+	return c.SessionID
+}
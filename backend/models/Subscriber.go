@@ -0,0 +1,58 @@
+package models
+
+import "sync"
+
+// Subscriber receives the messages of a hub room over some transport, a websocket Client or a StreamClient.
+// Every transport gets the same messages, only the way they are written out differs.
+type Subscriber interface {
+	// Room the subscriber receives the messages of, a game ID, a ticket ID, a tournament ID or LobbyRoomID
+	Room() string
+	// Session of the player, empty for spectators and lobby browsers
+	Session() string
+	// Deliver queues the message without blocking, false means the subscriber cannot keep up
+	Deliver(message Message) bool
+	// Close ends the subscription, the hub calls it once when the subscriber is removed
+	Close()
+}
+
+// Size of the queue of messages waiting to be written to a subscriber
+const subscriberQueueSize = 256
+
+// outbox is the queue of messages waiting to be written to a subscriber.
+// Deliver and Close take the same lock, so a message is never sent on a queue that is being closed.
+type outbox struct {
+	Send   chan Message
+	lock   sync.Mutex
+	closed bool
+}
+
+func newOutbox() outbox {
+	return outbox{Send: make(chan Message, subscriberQueueSize)}
+}
+
+// Deliver queues a message without blocking, false when the queue is full or closed
+func (o *outbox) Deliver(message Message) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.closed {
+		return false
+	}
+	select {
+	case o.Send <- message:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close closes the queue, the writer stops once it has drained it. Closing twice does nothing.
+func (o *outbox) Close() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if !o.closed {
+		o.closed = true
+		close(o.Send)
+	}
+}
@@ -0,0 +1,519 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ProlificLabs/captrivia/utils"
+)
+
+type TournamentFormat string
+
+const (
+	// Losers are knocked out until one player is left
+	TournamentSingleElimination TournamentFormat = "single-elimination"
+	// Everyone plays every round against players with a similar number of points
+	TournamentSwiss TournamentFormat = "swiss"
+)
+
+func (format TournamentFormat) IsValid() bool {
+	return format == TournamentSingleElimination || format == TournamentSwiss
+}
+
+type TournamentStatus string
+
+const (
+	TournamentRegistration TournamentStatus = "registration"
+	TournamentInProgress   TournamentStatus = "in-progress"
+	TournamentFinished     TournamentStatus = "finished"
+)
+
+var (
+	ErrTournamentNotFound      = errors.New("tournament not found")
+	ErrTournamentStarted       = errors.New("tournament has already started")
+	ErrAlreadyRegistered       = errors.New("player is already registered")
+	ErrNotEnoughPlayers        = errors.New("at least two players are needed to start the tournament")
+	ErrTournamentMatchNotFound = errors.New("tournament match not found")
+	ErrNotTournamentPlayer     = errors.New("player is not in this tournament")
+)
+
+type TournamentPlayer struct {
+	PlayerID   string  `json:"playerId"`
+	Name       string  `json:"name"`
+	Points     float64 `json:"points"`
+	Eliminated bool    `json:"eliminated"`
+	HadBye     bool    `json:"hadBye"`
+}
+
+// TournamentMatch is a game between two players, or a bye when only one player is set
+type TournamentMatch struct {
+	ID        string   `json:"id"`
+	Round     int      `json:"round"`
+	PlayerIDs []string `json:"playerIds"`
+	GameID    string   `json:"gameId"`
+	// Session of each player in the match game, keyed by player ID. Sessions are secrets,
+	// a player only gets their own one from GET /tournaments/:id/match.
+	SessionIDs map[string]string `json:"-"`
+	// Players who have not answered any question by then forfeit the match
+	Deadline time.Time      `json:"deadline,omitempty"`
+	Scores   map[string]int `json:"scores"`
+	// Empty for a draw
+	Winner   string `json:"winner"`
+	Finished bool   `json:"finished"`
+}
+
+// MatchResult is the final standing of one player in a match game
+type MatchResult struct {
+	PlayerID string
+	Score    int
+	Finished time.Time
+}
+
+type Tournament struct {
+	sync.Mutex
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Secret given to the organizer when the tournament is created
+	OrganizerToken string           `json:"-"`
+	Format         TournamentFormat `json:"format"`
+	Questions      int              `json:"questions"`
+	Category       string           `json:"category"`
+	// Number of rounds of a Swiss tournament, decided when it starts
+	Rounds int `json:"rounds"`
+	// Set when Rounds was decided from the number of players rather than by the organizer
+	autoRounds bool
+	// Seconds the players of a match have to play its game
+	MatchSeconds int                 `json:"matchSeconds"`
+	CurrentRound int                 `json:"currentRound"`
+	Status       TournamentStatus    `json:"status"`
+	Players      []*TournamentPlayer `json:"players"`
+	Matches      []*TournamentMatch  `json:"matches"`
+	Winner       string              `json:"winner"`
+}
+
+// Future improvement - store tournaments in a database
+var Tournaments = make(map[string]*Tournament)
+
+// TournamentsLock guards access to the Tournaments map
+var TournamentsLock sync.RWMutex
+
+func NewTournament(name string, format TournamentFormat, questions int, category string, rounds int, matchSeconds int) *Tournament {
+	tournament := &Tournament{
+		ID:             utils.GenerateRandomID(),
+		Name:           name,
+		OrganizerToken: utils.GenerateRandomID(),
+		Format:         format,
+		Questions:      questions,
+		Category:       category,
+		Rounds:         rounds,
+		MatchSeconds:   matchSeconds,
+		Status:         TournamentRegistration,
+		Players:        make([]*TournamentPlayer, 0),
+		Matches:        make([]*TournamentMatch, 0),
+	}
+
+	TournamentsLock.Lock()
+	defer TournamentsLock.Unlock()
+	Tournaments[tournament.ID] = tournament
+	return tournament
+}
+
+func GetTournament(tournamentID string) (*Tournament, error) {
+	TournamentsLock.RLock()
+	defer TournamentsLock.RUnlock()
+
+	tournament, exists := Tournaments[tournamentID]
+	if !exists {
+		return nil, ErrTournamentNotFound
+	}
+	return tournament, nil
+}
+
+// JSON returns the current bracket state
+func (tournament *Tournament) JSON() ([]byte, error) {
+	tournament.Lock()
+	defer tournament.Unlock()
+
+	return json.Marshal(tournament)
+}
+
+func (tournament *Tournament) AddPlayer(playerID string, name string) error {
+	tournament.Lock()
+	defer tournament.Unlock()
+
+	if tournament.Status != TournamentRegistration {
+		return ErrTournamentStarted
+	}
+	for _, player := range tournament.Players {
+		if player.PlayerID == playerID {
+			return ErrAlreadyRegistered
+		}
+	}
+
+	tournament.Players = append(tournament.Players, &TournamentPlayer{PlayerID: playerID, Name: name})
+	return nil
+}
+
+// Start closes registration and pairs the first round, it returns the matches that need a game
+func (tournament *Tournament) Start(ratings *RatingStore) ([]*TournamentMatch, error) {
+	tournament.Lock()
+	defer tournament.Unlock()
+
+	if tournament.Status != TournamentRegistration {
+		return nil, ErrTournamentStarted
+	}
+	if len(tournament.Players) < 2 {
+		return nil, ErrNotEnoughPlayers
+	}
+
+	// Seed the players by rating, the best player first
+	sort.SliceStable(tournament.Players, func(i, j int) bool {
+		return ratings.RatingOf(tournament.Players[i].PlayerID) > ratings.RatingOf(tournament.Players[j].PlayerID)
+	})
+
+	if tournament.Format == TournamentSwiss && tournament.Rounds <= 0 {
+		tournament.Rounds = int(math.Ceil(math.Log2(float64(len(tournament.Players)))))
+		tournament.autoRounds = true
+	}
+
+	tournament.Status = TournamentInProgress
+	return tournament.pairRound(), nil
+}
+
+// PlayerName returns the name the player registered with
+func (tournament *Tournament) PlayerName(playerID string) string {
+	tournament.Lock()
+	defer tournament.Unlock()
+
+	if player := tournament.player(playerID); player != nil {
+		return player.Name
+	}
+	return ""
+}
+
+func (tournament *Tournament) player(playerID string) *TournamentPlayer {
+	for _, player := range tournament.Players {
+		if player.PlayerID == playerID {
+			return player
+		}
+	}
+	return nil
+}
+
+// pairRound creates the matches of the next round, the caller must hold the lock.
+// Byes are finished right away, the other matches are returned so a game can be created for them.
+func (tournament *Tournament) pairRound() []*TournamentMatch {
+	tournament.CurrentRound++
+
+	var pairs [][]string
+	if tournament.Format == TournamentSwiss {
+		pairs = tournament.swissPairs()
+	} else {
+		pairs = tournament.eliminationPairs()
+	}
+
+	matches := make([]*TournamentMatch, 0, len(pairs))
+	for _, pair := range pairs {
+		match := &TournamentMatch{
+			ID:         utils.GenerateRandomID(),
+			Round:      tournament.CurrentRound,
+			PlayerIDs:  pair,
+			SessionIDs: make(map[string]string),
+			Scores:     make(map[string]int),
+		}
+		tournament.Matches = append(tournament.Matches, match)
+
+		if len(pair) == 1 {
+			player := tournament.player(pair[0])
+			player.HadBye = true
+			player.Points++
+			match.Winner = pair[0]
+			match.Finished = true
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// eliminationPairs pairs the remaining players. The first round pairs the best seed with the
+// worst, later rounds pair the winners of neighbouring matches. An odd player out gets a bye.
+func (tournament *Tournament) eliminationPairs() [][]string {
+	remaining := make([]string, 0)
+	if tournament.CurrentRound == 1 {
+		for _, player := range tournament.Players {
+			remaining = append(remaining, player.PlayerID)
+		}
+	} else {
+		for _, match := range tournament.Matches {
+			if match.Round == tournament.CurrentRound-1 && match.Winner != "" {
+				remaining = append(remaining, match.Winner)
+			}
+		}
+	}
+
+	pairs := make([][]string, 0)
+	if len(remaining)%2 == 1 {
+		pairs = append(pairs, []string{remaining[0]})
+		remaining = remaining[1:]
+	}
+
+	if tournament.CurrentRound == 1 {
+		for i := 0; i < len(remaining)/2; i++ {
+			pairs = append(pairs, []string{remaining[i], remaining[len(remaining)-1-i]})
+		}
+		return pairs
+	}
+	for i := 0; i+1 < len(remaining); i += 2 {
+		pairs = append(pairs, []string{remaining[i], remaining[i+1]})
+	}
+	return pairs
+}
+
+// swissPairs pairs players with similar points who have not played each other yet when possible.
+// An odd player out, the lowest ranked one without a bye so far, gets a bye.
+func (tournament *Tournament) swissPairs() [][]string {
+	standings := append([]*TournamentPlayer{}, tournament.Players...)
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Points > standings[j].Points
+	})
+
+	played := make(map[string]bool)
+	for _, match := range tournament.Matches {
+		if len(match.PlayerIDs) == 2 {
+			played[match.PlayerIDs[0]+":"+match.PlayerIDs[1]] = true
+			played[match.PlayerIDs[1]+":"+match.PlayerIDs[0]] = true
+		}
+	}
+
+	pairs := make([][]string, 0)
+	if len(standings)%2 == 1 {
+		byeIndex := len(standings) - 1
+		for i := len(standings) - 1; i >= 0; i-- {
+			if !standings[i].HadBye {
+				byeIndex = i
+				break
+			}
+		}
+		pairs = append(pairs, []string{standings[byeIndex].PlayerID})
+		standings = append(standings[:byeIndex:byeIndex], standings[byeIndex+1:]...)
+	}
+
+	paired := make(map[string]bool)
+	for i, player := range standings {
+		if paired[player.PlayerID] {
+			continue
+		}
+
+		opponent := ""
+		for _, candidate := range standings[i+1:] {
+			if paired[candidate.PlayerID] {
+				continue
+			}
+			if opponent == "" {
+				opponent = candidate.PlayerID
+			}
+			if !played[player.PlayerID+":"+candidate.PlayerID] {
+				opponent = candidate.PlayerID
+				break
+			}
+		}
+
+		paired[player.PlayerID] = true
+		paired[opponent] = true
+		pairs = append(pairs, []string{player.PlayerID, opponent})
+	}
+	return pairs
+}
+
+// UndoRound takes back the pairing of the current round when the games of its matches could not be created.
+// A tournament whose first round is undone is open for registration again.
+func (tournament *Tournament) UndoRound() {
+	tournament.Lock()
+	defer tournament.Unlock()
+
+	kept := make([]*TournamentMatch, 0, len(tournament.Matches))
+	for _, match := range tournament.Matches {
+		if match.Round != tournament.CurrentRound {
+			kept = append(kept, match)
+			continue
+		}
+		// Byes are scored as soon as they are paired
+		if len(match.PlayerIDs) == 1 {
+			player := tournament.player(match.PlayerIDs[0])
+			player.HadBye = false
+			player.Points--
+		}
+	}
+	tournament.Matches = kept
+	tournament.CurrentRound--
+
+	if tournament.CurrentRound == 0 {
+		tournament.Status = TournamentRegistration
+		if tournament.autoRounds {
+			tournament.Rounds = 0
+			tournament.autoRounds = false
+		}
+	}
+}
+
+// SetMatchGame records the game created for a match, the session of every player in it and when it has to be played by
+func (tournament *Tournament) SetMatchGame(matchID string, gameID string, sessionIDs map[string]string, deadline time.Time) {
+	tournament.Lock()
+	defer tournament.Unlock()
+
+	for _, match := range tournament.Matches {
+		if match.ID == matchID {
+			match.GameID = gameID
+			match.SessionIDs = sessionIDs
+			match.Deadline = deadline
+			return
+		}
+	}
+}
+
+// CurrentMatch returns a copy of the match the player has to play now, with only their own session
+func (tournament *Tournament) CurrentMatch(playerID string) (TournamentMatch, error) {
+	tournament.Lock()
+	defer tournament.Unlock()
+
+	if tournament.player(playerID) == nil {
+		return TournamentMatch{}, ErrNotTournamentPlayer
+	}
+	for _, match := range tournament.Matches {
+		sessionID, exists := match.SessionIDs[playerID]
+		if match.Finished || !exists {
+			continue
+		}
+
+		copied := *match
+		copied.PlayerIDs = append([]string{}, match.PlayerIDs...)
+		copied.SessionIDs = map[string]string{playerID: sessionID}
+		copied.Scores = make(map[string]int, len(match.Scores))
+		for player, score := range match.Scores {
+			copied.Scores[player] = score
+		}
+		return copied, nil
+	}
+	return TournamentMatch{}, ErrTournamentMatchNotFound
+}
+
+// RecordResult finishes the match played in the game. The higher score wins, in single elimination
+// the player who finished first wins a tie. Once every match of the round is finished the tournament
+// either ends or pairs the next round, whose matches needing a game are returned.
+func (tournament *Tournament) RecordResult(gameID string, results []MatchResult) ([]*TournamentMatch, error) {
+	tournament.Lock()
+	defer tournament.Unlock()
+
+	var match *TournamentMatch
+	for _, candidate := range tournament.Matches {
+		if candidate.GameID == gameID {
+			match = candidate
+			break
+		}
+	}
+	if match == nil {
+		return nil, ErrTournamentMatchNotFound
+	}
+	if match.Finished {
+		return nil, nil
+	}
+
+	// Players of the match come first in seed order, so a full tie goes to the higher seed
+	seeds := make(map[string]int, len(match.PlayerIDs))
+	for i, playerID := range match.PlayerIDs {
+		seeds[playerID] = i
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return seeds[results[i].PlayerID] < seeds[results[j].PlayerID]
+	})
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		// Players who never finished lose ties to those who did
+		if results[i].Finished.IsZero() != results[j].Finished.IsZero() {
+			return !results[i].Finished.IsZero()
+		}
+		return results[i].Finished.Before(results[j].Finished)
+	})
+
+	for _, result := range results {
+		match.Scores[result.PlayerID] = result.Score
+	}
+
+	// Players that left the game or never played forfeit the match
+	for _, playerID := range match.PlayerIDs {
+		if _, exists := match.Scores[playerID]; !exists {
+			match.Scores[playerID] = -1
+		}
+	}
+
+	draw := len(results) > 1 && results[0].Score == results[1].Score && tournament.Format == TournamentSwiss
+	switch {
+	case draw:
+	case len(results) > 0:
+		match.Winner = results[0].PlayerID
+	default:
+		// Nobody is left in the game, the higher seed goes through
+		match.Winner = match.PlayerIDs[0]
+	}
+	match.Finished = true
+
+	for _, playerID := range match.PlayerIDs {
+		player := tournament.player(playerID)
+		switch {
+		case draw:
+			player.Points += 0.5
+		case playerID == match.Winner:
+			player.Points++
+		case tournament.Format == TournamentSingleElimination:
+			player.Eliminated = true
+		}
+	}
+
+	for _, roundMatch := range tournament.Matches {
+		if roundMatch.Round == tournament.CurrentRound && !roundMatch.Finished {
+			return nil, nil
+		}
+	}
+
+	if tournament.isOver() {
+		tournament.finish()
+		return nil, nil
+	}
+	return tournament.pairRound(), nil
+}
+
+// isOver returns true once the last round has been played, the caller must hold the lock
+func (tournament *Tournament) isOver() bool {
+	if tournament.Format == TournamentSwiss {
+		return tournament.CurrentRound >= tournament.Rounds
+	}
+
+	remaining := 0
+	for _, player := range tournament.Players {
+		if !player.Eliminated {
+			remaining++
+		}
+	}
+	return remaining <= 1
+}
+
+// finish ends the tournament and picks the winner, the caller must hold the lock
+func (tournament *Tournament) finish() {
+	tournament.Status = TournamentFinished
+
+	standings := append([]*TournamentPlayer{}, tournament.Players...)
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Eliminated != standings[j].Eliminated {
+			return !standings[i].Eliminated
+		}
+		return standings[i].Points > standings[j].Points
+	})
+	tournament.Winner = standings[0].PlayerID
+}
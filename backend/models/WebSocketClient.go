@@ -24,14 +24,24 @@ const (
 //Client struct for websocket connection and message sending
 type Client struct {
 	ID   string
+	// Session of the player using this connection, empty for spectators and lobby browsers
+	SessionID string
 	Conn *websocket.Conn
-	Send chan Message
+	outbox
 	hub  *Hub
 }
 
 //NewClient creates a new client
 func NewClient(id string, conn *websocket.Conn, hub *Hub) *Client {
-	return &Client{ID: id, Conn: conn, Send: make(chan Message, 256), hub: hub}
+	return &Client{ID: id, Conn: conn, outbox: newOutbox(), hub: hub}
+}
+
+func (c *Client) Room() string {
+	return c.ID
+}
+
+func (c *Client) Session() string {
+	return c.SessionID
 }
 
 //Client goroutine to read messages from client
@@ -88,8 +98,3 @@ func (c *Client) Write() {
 
 	}
 }
-
-//Client closing channel to unregister client
-func (c *Client) Close() {
-	close(c.Send)
-}
\ No newline at end of file
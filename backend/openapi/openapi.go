@@ -0,0 +1,10 @@
+// Package openapi holds the OpenAPI specification of the HTTP API.
+// Keep it in step with the handlers, the contract tests check real responses against it.
+package openapi
+
+import _ "embed"
+
+// Spec is the OpenAPI 3 document served at /openapi.json
+//
+//go:embed openapi.json
+var Spec []byte
@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func AnswerRoutes(router *gin.Engine) {
+func AnswerRoutes(router gin.IRouter) {
 	router.POST("/answer", controllers.AnswerHandler)
+	router.POST("/powerup", controllers.UsePowerUpHandler)
 }
\ No newline at end of file
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/ProlificLabs/captrivia/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+// APIPrefix is where the current version of the API is mounted
+const APIPrefix = "/api/v1"
+
+// apiRoutes registers every route of the API on the router
+func apiRoutes(router gin.IRouter) {
+	GameRoutes(router)
+	AnswerRoutes(router)
+	LeaderboardRoutes(router)
+	MatchmakingRoutes(router)
+	PlayerRoutes(router)
+	TournamentRoutes(router)
+	OpenAPIRoutes(router)
+}
+
+// APIRoutes mounts the API under APIPrefix. The same routes stay at the root for clients that
+// have not moved yet, their responses say they are deprecated and where the successor is.
+func APIRoutes(router *gin.Engine) {
+	apiRoutes(router.Group(APIPrefix))
+	apiRoutes(router.Group("", controllers.DeprecatedRouteMiddleware(APIPrefix)))
+}
@@ -6,10 +6,16 @@ import (
 )
 
 // GameRoutes defines the routes for the game.
-func GameRoutes(router *gin.Engine) {
+func GameRoutes(router gin.IRouter) {
 	router.GET("/game/:gameID/:sessionID", controllers.GetGameHandler)
+	router.GET("/game/:gameID/:sessionID/review", controllers.GameReviewHandler)
 	router.GET("/game/:gameID/ws", controllers.GameWebSocketHandler)
+	router.GET("/game/:gameID/events", controllers.GameEventsHandler)
 	router.POST("/game/start", controllers.StartGameHandler)
 	router.POST("/game/join", controllers.JoinGameHandler)
 	router.POST("/game/end", controllers.EndGameHandler)
+	router.POST("/game/kick", controllers.KickPlayerHandler)
+	router.POST("/game/ban", controllers.BanPlayerHandler)
+	router.GET("/games", controllers.ListGamesHandler)
+	router.GET("/games/ws", controllers.LobbyWebSocketHandler)
 }
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/ProlificLabs/captrivia/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+// LeaderboardRoutes defines the routes for the leaderboards and player ratings.
+func LeaderboardRoutes(router gin.IRouter) {
+	router.GET("/leaderboard", controllers.LeaderboardHandler)
+	router.GET("/daily/leaderboard", controllers.DailyLeaderboardHandler)
+	router.GET("/ratings/:playerID", controllers.RatingHandler)
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/ProlificLabs/captrivia/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+// MatchmakingRoutes defines the routes for the quick-match queue.
+func MatchmakingRoutes(router gin.IRouter) {
+	router.POST("/matchmaking/enqueue", controllers.EnqueueHandler)
+	router.POST("/matchmaking/cancel", controllers.CancelMatchmakingHandler)
+	router.GET("/matchmaking/:ticketID", controllers.MatchmakingStatusHandler)
+	router.GET("/matchmaking/:ticketID/ws", controllers.MatchmakingWebSocketHandler)
+}
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/ProlificLabs/captrivia/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPIRoutes serves the specification of the API.
+func OpenAPIRoutes(router gin.IRouter) {
+	router.GET("/openapi.json", controllers.OpenAPIHandler)
+}
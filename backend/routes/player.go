@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/ProlificLabs/captrivia/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+// PlayerRoutes defines the routes for player profiles and match history.
+func PlayerRoutes(router gin.IRouter) {
+	router.GET("/players/me", controllers.CurrentPlayerHandler)
+	router.GET("/players/:playerID", controllers.PlayerProfileHandler)
+	router.GET("/players/:playerID/games", controllers.PlayerGamesHandler)
+}
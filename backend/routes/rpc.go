@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/ProlificLabs/captrivia/controllers"
+	"github.com/ProlificLabs/captrivia/gen/captrivia/v1/captriviav1connect"
+	"github.com/gin-gonic/gin"
+)
+
+// RPCRoutes serves the GameService over Connect, gRPC and gRPC-Web. gRPC clients expect the
+// service at the root, so it is not mounted under APIPrefix.
+func RPCRoutes(router gin.IRouter) {
+	path, handler := captriviav1connect.NewGameServiceHandler(controllers.NewGameRPCService())
+	router.Any(path+"*method", gin.WrapH(handler))
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/ProlificLabs/captrivia/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+// TournamentRoutes defines the routes for organizing tournaments and following their brackets.
+func TournamentRoutes(router gin.IRouter) {
+	router.POST("/tournaments", controllers.CreateTournamentHandler)
+	router.GET("/tournaments/:tournamentID", controllers.GetTournamentHandler)
+	router.POST("/tournaments/:tournamentID/players", controllers.RegisterTournamentPlayerHandler)
+	router.POST("/tournaments/:tournamentID/start", controllers.StartTournamentHandler)
+	router.GET("/tournaments/:tournamentID/match", controllers.GetTournamentMatchHandler)
+	router.GET("/tournaments/:tournamentID/ws", controllers.TournamentWebSocketHandler)
+}
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ReadJSONFile decodes the file at path into value, a missing file leaves value untouched
+func ReadJSONFile(path string, value interface{}) error {
+	fileBytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(fileBytes, value)
+}
+
+// WriteJSONFile replaces the file at path with the JSON encoding of value
+func WriteJSONFile(path string, value interface{}) error {
+	fileBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return replaceFile(path, fileBytes)
+}
+
+// ReadJSONLines calls read with every line of the file at path, a missing file has no lines
+func ReadJSONLines(path string, read func(line []byte) error) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if readErr := read(line); readErr != nil {
+				return readErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// AppendJSONLine adds the JSON encoding of value to the end of the file at path as a line of its own
+func AppendJSONLine(path string, value interface{}) error {
+	lineBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(lineBytes, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteJSONLines replaces the file at path with the JSON encoding of every value on a line of its own
+func WriteJSONLines(path string, values []interface{}) error {
+	var fileBytes bytes.Buffer
+	for _, value := range values {
+		lineBytes, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		fileBytes.Write(lineBytes)
+		fileBytes.WriteByte('\n')
+	}
+	return replaceFile(path, fileBytes.Bytes())
+}
+
+// replaceFile writes the data to a temporary file first so readers never see a partial file
+func replaceFile(path string, fileBytes []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, fileBytes, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, path)
+}
@@ -1,8 +1,15 @@
-import { AnswerResponse, EndGameResponse, Game, GameSession } from "../models";
+import {
+  Answer,
+  AnswerResponse,
+  EndGameResponse,
+  Game,
+  GameSession,
+} from "../models";
 
-// Use REACT_APP_BACKEND_URL or http://localhost:8080 as the API_BASE
-const API_BASE =
-  import.meta.env.REACT_APP_BACKEND_URL || "http://localhost:8080";
+// Use REACT_APP_BACKEND_URL or http://localhost:8080 as the backend, the API is served under /api/v1
+const API_BASE = `${
+  import.meta.env.REACT_APP_BACKEND_URL || "http://localhost:8080"
+}/api/v1`;
 
 /**
  * Wrapper around fetch to handle errors and parsing JSON
@@ -15,7 +22,8 @@ const fetchWrapper = async <T>(
   options?: RequestInit
 ): Promise<T> => {
   try {
-    const res = await fetch(url, options);
+    // The player ID is kept in a cookie the backend sets, send it with every request
+    const res = await fetch(url, { credentials: "include", ...options });
     const data = await res.json();
     if (!res.ok) {
       throw new Error(data.error || "Failed to fetch.");
@@ -112,7 +120,7 @@ export const joinGame = async (
  * @param gameId - Id of the game
  * @param sessionId - Id of the session
  * @param questionId - Id of the question
- * @param answer - Index of the answer
+ * @param answer - Answer in the shape of the question type, e.g. the index of the picked option
  * @returns - Object with correct and currentScore
  * @throws - Error if failed to submit answer
  * @example
@@ -123,7 +131,7 @@ export const submitAnswer = async (
   gameId: string,
   sessionId: string,
   questionId: string,
-  answer: number
+  answer: Answer
 ): Promise<AnswerResponse> => {
   try {
     return await fetchWrapper<AnswerResponse>(`${API_BASE}/answer`, {
@@ -0,0 +1,174 @@
+import ArrowDownwardIcon from "@mui/icons-material/ArrowDownward";
+import ArrowUpwardIcon from "@mui/icons-material/ArrowUpward";
+import {
+  Button,
+  Checkbox,
+  FormControlLabel,
+  FormGroup,
+  IconButton,
+  Stack,
+  TextField,
+} from "@mui/material";
+import { useState } from "react";
+import { Answer, Question } from "../../models";
+
+interface AnswerInputProps {
+  question: Question;
+  submitAnswer: (answer: Answer) => void;
+}
+
+export const SingleChoiceInput: React.FC<AnswerInputProps> = ({
+  question,
+  submitAnswer,
+}) => {
+  return (
+    <Stack spacing={2}>
+      {question.options.map((option, index) => (
+        <Button
+          variant="contained"
+          onClick={() => submitAnswer(index)}
+          key={index}
+        >
+          {option}
+        </Button>
+      ))}
+    </Stack>
+  );
+};
+
+export const TrueFalseInput: React.FC<AnswerInputProps> = ({
+  submitAnswer,
+}) => {
+  return (
+    <Stack spacing={2}>
+      <Button variant="contained" onClick={() => submitAnswer(true)}>
+        True
+      </Button>
+      <Button variant="contained" onClick={() => submitAnswer(false)}>
+        False
+      </Button>
+    </Stack>
+  );
+};
+
+export const MultiSelectInput: React.FC<AnswerInputProps> = ({
+  question,
+  submitAnswer,
+}) => {
+  const [picked, setPicked] = useState<number[]>([]);
+
+  const toggle = (index: number) => {
+    setPicked((picked) =>
+      picked.includes(index)
+        ? picked.filter((pickedIndex) => pickedIndex !== index)
+        : [...picked, index].sort((a, b) => a - b)
+    );
+  };
+
+  return (
+    <Stack spacing={2}>
+      <FormGroup>
+        {question.options.map((option, index) => (
+          <FormControlLabel
+            key={index}
+            control={
+              <Checkbox
+                checked={picked.includes(index)}
+                onChange={() => toggle(index)}
+              />
+            }
+            label={option}
+          />
+        ))}
+      </FormGroup>
+      <Button
+        variant="contained"
+        disabled={picked.length === 0}
+        onClick={() => submitAnswer(picked)}
+      >
+        Submit
+      </Button>
+    </Stack>
+  );
+};
+
+export const NumericInput: React.FC<AnswerInputProps> = ({
+  submitAnswer,
+}) => {
+  const [value, setValue] = useState("");
+  const number = Number(value);
+  const valid = value.trim() !== "" && Number.isFinite(number);
+
+  return (
+    <Stack spacing={2}>
+      <TextField
+        label="Your answer"
+        type="number"
+        value={value}
+        onChange={(event) => setValue(event.target.value)}
+      />
+      <Button
+        variant="contained"
+        disabled={!valid}
+        onClick={() => submitAnswer(number)}
+      >
+        Submit
+      </Button>
+    </Stack>
+  );
+};
+
+export const OrderingInput: React.FC<AnswerInputProps> = ({
+  question,
+  submitAnswer,
+}) => {
+  // Option indexes in the order the player has put them
+  const [order, setOrder] = useState<number[]>(
+    question.options.map((_, index) => index)
+  );
+
+  const move = (position: number, offset: number) => {
+    setOrder((order) => {
+      const moved = [...order];
+      [moved[position], moved[position + offset]] = [
+        moved[position + offset],
+        moved[position],
+      ];
+      return moved;
+    });
+  };
+
+  return (
+    <Stack spacing={2}>
+      {order.map((optionIndex, position) => (
+        <Stack
+          direction="row"
+          alignItems="center"
+          spacing={1}
+          key={optionIndex}
+        >
+          <span style={{ flexGrow: 1 }}>
+            {position + 1}. {question.options[optionIndex]}
+          </span>
+          <IconButton
+            aria-label="Move up"
+            disabled={position === 0}
+            onClick={() => move(position, -1)}
+          >
+            <ArrowUpwardIcon />
+          </IconButton>
+          <IconButton
+            aria-label="Move down"
+            disabled={position === order.length - 1}
+            onClick={() => move(position, 1)}
+          >
+            <ArrowDownwardIcon />
+          </IconButton>
+        </Stack>
+      ))}
+      <Button variant="contained" onClick={() => submitAnswer(order)}>
+        Submit
+      </Button>
+    </Stack>
+  );
+};
@@ -1,28 +1,44 @@
-import { Button, Stack } from "@mui/material";
-import { Question } from "../../models";
+import { Answer, Question } from "../../models";
+import {
+  MultiSelectInput,
+  NumericInput,
+  OrderingInput,
+  SingleChoiceInput,
+  TrueFalseInput,
+} from "./AnswerInputs";
 
 interface QuestionProps {
   question: Question;
-  submitAnswer: (index: number) => void;
+  submitAnswer: (answer: Answer) => void;
 }
+
+// Inputs of every question type, questions without a type are single choice
+const answerInputs = {
+  singleChoice: SingleChoiceInput,
+  trueFalse: TrueFalseInput,
+  multiSelect: MultiSelectInput,
+  numeric: NumericInput,
+  ordering: OrderingInput,
+};
+
 const QuestionComponent: React.FC<QuestionProps> = ({
   question,
   submitAnswer,
 }) => {
+  if (!question) {
+    return null;
+  }
+
+  const AnswerInput = answerInputs[question.type ?? "singleChoice"];
   return (
     <>
-      <h3>{question?.questionText}</h3>
-      <Stack spacing={2}>
-        {question?.options.map((option, index) => (
-          <Button
-            variant="contained"
-            onClick={() => submitAnswer(index)}
-            key={index}
-          >
-            {option}
-          </Button>
-        ))}
-      </Stack>
+      <h3>{question.questionText}</h3>
+      {/* The key starts every question with an empty answer */}
+      <AnswerInput
+        key={question.id}
+        question={question}
+        submitAnswer={submitAnswer}
+      />
     </>
   );
 };
@@ -1,12 +1,12 @@
 import { Grid } from "@mui/material";
-import { Question } from "../../models";
+import { Answer, Question } from "../../models";
 import QuestionComponent from "./Question";
 import ScoreComponent from "./Score";
 
 interface QuestionsContainerProps {
   questions: Question[];
   currentQuestionIndex: number;
-  submitAnswer: (index: number) => void;
+  submitAnswer: (answer: Answer) => void;
   score: number;
 }
 const QuestionsContainer = ({
@@ -27,12 +27,22 @@ export interface EndGameResponse {
   finalScore: number;
   multiplayer: boolean;
   finished: boolean;
-  leaderboard: { [name: string]: number };
+  leaderboard: LeaderboardEntry[];
 
   // This will be sent if multiplayer
   players?: ScoreUpdate[];
 }
 
+export interface LeaderboardEntry {
+  rank: number;
+  playerId: string;
+  name: string;
+  gamesPlayed: number;
+  accuracy: number;
+  bestScore: number;
+  totalScore: number;
+}
+
 export interface ScoreUpdate {
   name: string;
   score: number;
@@ -1,6 +1,24 @@
+// Questions without a type are single choice
+export type QuestionType =
+  | "singleChoice"
+  | "trueFalse"
+  | "multiSelect"
+  | "numeric"
+  | "ordering";
+
 export interface Question {
   id: string;
   questionText: string;
   options: string[];
   correctIndex: number;
+  type?: QuestionType;
+  // How far off the answer of a numeric question can be
+  tolerance?: number;
 }
+
+/**
+ * Answer is shaped after the type of the question: the option index of a single choice question,
+ * true or false, the picked option indexes of a multi-select question, a number, or every option
+ * index in order for an ordering question
+ */
+export type Answer = number | boolean | number[];
@@ -122,4 +122,54 @@ describe("Game", () => {
       ).toBeInTheDocument();
     });
   });
+
+  it("submits answers in the shape of the question type", async () => {
+    mockCurrentGame.mockReturnValue({ gameId: "123", sessionId: "Game 1" });
+    vi.spyOn(Api, "fetchGame").mockResolvedValue({
+      ...gameData,
+      questions: [
+        {
+          id: "q1",
+          questionText: "Paris is the capital of France",
+          options: [],
+          correctIndex: 0,
+          type: "trueFalse",
+        },
+        {
+          id: "q2",
+          questionText: "Which of these are capitals?",
+          options: ["Paris", "Lyon", "Tokyo"],
+          correctIndex: 0,
+          type: "multiSelect",
+        },
+      ],
+    });
+    const submitSpy = vi
+      .spyOn(Api, "submitAnswer")
+      .mockResolvedValue(answerReponse);
+
+    renderGame();
+    await act(async () => {});
+
+    await act(async () => {
+      userEvent.click(screen.getByText("True"));
+    });
+    await waitFor(() => {
+      expect(submitSpy).toHaveBeenCalledWith("123", "Game 1", "q1", true);
+      expect(
+        screen.getByText("Which of these are capitals?")
+      ).toBeInTheDocument();
+    });
+
+    await act(async () => {
+      userEvent.click(screen.getByLabelText("Tokyo"));
+      userEvent.click(screen.getByLabelText("Paris"));
+    });
+    await act(async () => {
+      userEvent.click(screen.getByText("Submit"));
+    });
+    await waitFor(() => {
+      expect(submitSpy).toHaveBeenCalledWith("123", "Game 1", "q2", [0, 2]);
+    });
+  });
 });
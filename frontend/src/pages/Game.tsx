@@ -7,6 +7,7 @@ import PlayerScores from "../components/PlayerScores";
 import WaitingForGameStart from "../components/WaitingForGameStart";
 import QuestionsContainer from "../components/questions/QuestionsContainer";
 import {
+  Answer,
   Game as GameModel,
   GameSession,
   Question,
@@ -69,13 +70,13 @@ const Game: React.FC = () => {
     }
   };
 
-  const submitAnswerHandler = async (index: number) => {
+  const submitAnswerHandler = async (answer: Answer) => {
     try {
       const answerResponse = await submitAnswer(
         currentGameState?.gameId!,
         currentGameState?.sessionId!,
         questions[currentQuestionIndex].id,
-        index
+        answer
       );
 
       if (answerResponse.correct) {
@@ -110,7 +111,9 @@ const Game: React.FC = () => {
 
   useEffect(() => {
     if (game && game.multiplayer) {
-      const socket = new Socket(`/game/${game.id}/ws`);
+      const socket = new Socket(
+        `/game/${game.id}/ws?sessionId=${currentGameState?.sessionId}`
+      );
       setSocket(socket);
 
       socket.on(SocketEventNames.CONNECT, () => {
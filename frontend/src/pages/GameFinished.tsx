@@ -127,9 +127,9 @@ const GameFinished = () => {
           <div>
             <h3>Leaderboard</h3>
             <ol>
-              {Object.keys(endGameStats?.leaderboard).map((key, index) => (
-                <li key={index}>
-                  {key} - {endGameStats?.leaderboard[key]}%
+              {endGameStats?.leaderboard.map((entry) => (
+                <li key={entry.playerId}>
+                  {entry.name} - {entry.accuracy}%
                 </li>
               ))}
             </ol>
@@ -11,9 +11,10 @@ export enum SocketEventNames {
   START_GAME_COUNTDOWN = "startGameCountdown",
 }
 
-// Use REACT_APP_SOCKET_URL or ws:/localhost:8080 as the SOCKET_BASE
-const SOCKET_BASE =
-  import.meta.env.REACT_APP_SOCKET_URL || "ws:/localhost:8080";
+// Use REACT_APP_SOCKET_URL or ws://localhost:8080 as the backend, the sockets are served under /api/v1
+const SOCKET_BASE = `${
+  import.meta.env.REACT_APP_SOCKET_URL || "ws://localhost:8080"
+}/api/v1`;
 
 /**
  * Socket is a wrapper around the WebSocket API that provides a simple event-based interface.